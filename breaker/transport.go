@@ -0,0 +1,125 @@
+package breaker
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"openapi-to-graphql/metrics"
+)
+
+// Circuit states, values of circuit breaker state metric
+const (
+	Closed   = 0
+	HalfOpen = 1
+	Open     = 2
+)
+
+type circuit struct {
+	state    int
+	failures int
+	opened   time.Time
+	// request of half-open circuit is in flight
+	probing bool
+}
+
+// Circuit breaker of upstream hosts. Circuit opens after consecutive failures, i.e. request errors and 5xx responses,
+// and requests fail without calling upstream. After cooldown one request is let through, the circuit closes if it succeeds
+// and opens again if it fails. Cancelled requests are not failures of upstream
+type Transport struct {
+	failures int
+	cooldown time.Duration
+	next     http.RoundTripper
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// Returns transport opening circuit of host after failures consecutive failures of next (http.DefaultTransport if nil)
+func NewTransport(failures int, cooldown time.Duration, next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{
+		failures: failures,
+		cooldown: cooldown,
+		next:     next,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	host := request.URL.Host
+	if !t.allow(host) {
+		metrics.ObserveCircuitRejection(host)
+		return nil, fmt.Errorf("Circuit breaker of upstream %s is open", host)
+	}
+
+	response, err := t.next.RoundTrip(request)
+	if err != nil && request.Context().Err() != nil {
+		t.release(host)
+		return response, err
+	}
+	t.record(host, err != nil || response.StatusCode >= 500)
+	return response, err
+}
+
+// Returns true if request to host can be sent. Open circuit becomes half-open after cooldown
+func (t *Transport) allow(host string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.getCircuit(host)
+	if c.state == Open && time.Since(c.opened) >= t.cooldown {
+		t.setState(host, c, HalfOpen)
+	}
+	switch c.state {
+	case Open:
+		return false
+	case HalfOpen:
+		if c.probing {
+			return false
+		}
+		c.probing = true
+	}
+	return true
+}
+
+// Lets next request of half-open circuit through, e.g. if probe was cancelled
+func (t *Transport) release(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.getCircuit(host).probing = false
+}
+
+func (t *Transport) record(host string, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.getCircuit(host)
+	c.probing = false
+	if !failed {
+		c.failures = 0
+		t.setState(host, c, Closed)
+		return
+	}
+	c.failures++
+	if c.state == HalfOpen || c.failures >= t.failures {
+		c.opened = time.Now()
+		t.setState(host, c, Open)
+	}
+}
+
+func (t *Transport) getCircuit(host string) *circuit {
+	c, ok := t.circuits[host]
+	if !ok {
+		c = &circuit{}
+		t.circuits[host] = c
+		metrics.SetCircuitState(host, Closed)
+	}
+	return c
+}
+
+func (t *Transport) setState(host string, c *circuit, state int) {
+	c.state = state
+	metrics.SetCircuitState(host, state)
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"openapi-to-graphql/metrics"
+)
+
+// Request headers which are part of cache key, responses of one user are not served to another
+var keyHeaders = []string{"Authorization", "Cookie", "Accept"}
+
+type entry struct {
+	key        string
+	statusCode int
+	header     http.Header
+	body       []byte
+	expires    time.Time
+}
+
+// Caches successful GET responses of upstream in memory. Requests match by URL and Authorization, Cookie and Accept
+// headers. Responses are cached for ttl or shorter max-age of Cache-Control, no-store and Vary: * responses are not cached.
+// The oldest entry is evicted when size is reached. Lookups are recorded as hits and misses
+type Transport struct {
+	ttl  time.Duration
+	size int
+	next http.RoundTripper
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// Returns transport caching up to size responses of next (http.DefaultTransport if nil)
+func NewTransport(ttl time.Duration, size int, next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{
+		ttl:     ttl,
+		size:    size,
+		next:    next,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != http.MethodGet {
+		return t.next.RoundTrip(request)
+	}
+
+	key := getKey(request)
+	if e := t.get(key); e != nil {
+		metrics.ObserveCacheLookup(true)
+		return &http.Response{
+			Status:        strconv.Itoa(e.statusCode) + " " + http.StatusText(e.statusCode),
+			StatusCode:    e.statusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        e.header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+			ContentLength: int64(len(e.body)),
+			Request:       request,
+		}, nil
+	}
+	metrics.ObserveCacheLookup(false)
+
+	response, err := t.next.RoundTrip(request)
+	if err != nil || response.StatusCode != http.StatusOK {
+		return response, err
+	}
+	ttl := t.getTTL(response.Header)
+	if ttl <= 0 {
+		return response, nil
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	t.put(&entry{key: key, statusCode: response.StatusCode, header: response.Header.Clone(), body: body, expires: time.Now().Add(ttl)})
+	return response, nil
+}
+
+// Returns ttl of response, 0 if response can't be cached
+func (t *Transport) getTTL(header http.Header) time.Duration {
+	if header.Get("Vary") == "*" {
+		return 0
+	}
+	ttl := t.ttl
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == "no-store" || directive == "no-cache" {
+			return 0
+		}
+		if strings.HasPrefix(directive, "max-age=") {
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil && time.Duration(seconds)*time.Second < ttl {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return ttl
+}
+
+// Returns entry of key, expired entry is removed
+func (t *Transport) get(key string) *entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	element, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	e := element.Value.(*entry)
+	if time.Now().After(e.expires) {
+		t.order.Remove(element)
+		delete(t.entries, key)
+		return nil
+	}
+	return e
+}
+
+func (t *Transport) put(e *entry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if element, ok := t.entries[e.key]; ok {
+		t.order.Remove(element)
+	}
+	t.entries[e.key] = t.order.PushBack(e)
+	for t.order.Len() > t.size {
+		oldest := t.order.Front()
+		t.order.Remove(oldest)
+		delete(t.entries, oldest.Value.(*entry).key)
+	}
+}
+
+func getKey(request *http.Request) string {
+	b := &strings.Builder{}
+	b.WriteString(request.URL.String())
+	for _, name := range keyHeaders {
+		b.WriteString("\n" + name + ": " + strings.Join(request.Header.Values(name), ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+// Request and response pair of upstream call
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	// Host and path of server URL before path template, e.g. /v1
+	Host         string `json:"host,omitempty"`
+	BasePath     string `json:"basePath,omitempty"`
+	Method       string `json:"method"`
+	PathTemplate string `json:"pathTemplate"`
+	Path         string `json:"path"`
+	Query        string `json:"query,omitempty"`
+	Body         string `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	// base64 if body is not valid UTF-8, e.g. image
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+}
+
+// Headers which differ on every call and make recorded cassettes noisy
+var skippedHeaders = []string{"Date"}
+
+func load(path string) ([]*Interaction, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var interactions []*Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, err
+	}
+	for _, interaction := range interactions {
+		interaction.Request.Query = normalizeQuery(interaction.Request.Query)
+		interaction.Request.Body = normalizeBody([]byte(interaction.Request.Body))
+	}
+	return interactions, nil
+}
+
+// Writes temporary file and renames it to path, so interrupted save doesn't corrupt cassette
+func save(path string, interactions []*Interaction) error {
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(interactions); err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func newResponse(response *http.Response, body []byte) Response {
+	headers := response.Header.Clone()
+	for _, header := range skippedHeaders {
+		headers.Del(header)
+	}
+	recorded := Response{
+		StatusCode: response.StatusCode,
+		Headers:    headers,
+		Body:       string(body),
+	}
+	if !utf8.Valid(body) {
+		recorded.Body = base64.StdEncoding.EncodeToString(body)
+		recorded.BodyEncoding = "base64"
+	}
+	return recorded
+}
+
+func (r Response) body() ([]byte, error) {
+	if r.BodyEncoding == "base64" {
+		return base64.StdEncoding.DecodeString(r.Body)
+	}
+	return []byte(r.Body), nil
+}
+
+// Query parameters are sorted by name
+func normalizeQuery(query string) string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return query
+	}
+	return values.Encode()
+}
+
+// JSON body is compacted with sorted keys, other bodies are compared as is
+func normalizeBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(normalized)
+}
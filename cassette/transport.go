@@ -0,0 +1,224 @@
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Mode string
+
+const (
+	// Calls upstream and records every interaction, cassette is saved on close
+	Record Mode = "record"
+	// Serves recorded interactions, upstream is not called
+	Replay Mode = "replay"
+)
+
+var pathParamRegexp = regexp.MustCompile(`\{[^}/]+\}`)
+
+type pathTemplate struct {
+	template string
+	regexp   *regexp.Regexp
+	literals int
+}
+
+// Records upstream interactions to cassette file or replays them. Requests match by host, base path of server, method,
+// path template, query and body, so merged specs with the same paths don't replay each other's interactions.
+// Recorded request with the same path is preferred, so different path parameters of the same template can be replayed.
+// Equal requests are replayed in recorded order, the last one is repeated. Interactions recorded without host match every host
+type Transport struct {
+	mode      Mode
+	path      string
+	next      http.RoundTripper
+	templates []*pathTemplate
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         map[*Interaction]bool
+}
+
+// Returns transport of cassette file. Path templates are OpenAPI paths, e.g. /pets/{id}.
+// Recording starts a new cassette, next is used to call upstream (http.DefaultTransport if nil)
+func NewTransport(mode Mode, path string, pathTemplates []string, next http.RoundTripper) (*Transport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &Transport{
+		mode:         mode,
+		path:         path,
+		next:         next,
+		interactions: make([]*Interaction, 0),
+		used:         make(map[*Interaction]bool),
+	}
+
+	for _, template := range pathTemplates {
+		literals := pathParamRegexp.Split(template, -1)
+		for i, literal := range literals {
+			literals[i] = regexp.QuoteMeta(literal)
+		}
+		// server URL may have base path, e.g. /v1
+		expression := "^(.*)" + strings.Join(literals, "[^/]+") + "$"
+		t.templates = append(t.templates, &pathTemplate{
+			template: template,
+			regexp:   regexp.MustCompile(expression),
+			literals: len(pathParamRegexp.ReplaceAllString(template, "")),
+		})
+	}
+	// more specific templates are matched first, e.g. /pets/findByTags before /pets/{id}
+	sort.SliceStable(t.templates, func(i, j int) bool {
+		return t.templates[i].literals > t.templates[j].literals
+	})
+
+	switch mode {
+	case Record:
+	case Replay:
+		interactions, err := load(path)
+		if err != nil {
+			return nil, err
+		}
+		t.interactions = interactions
+	default:
+		return nil, errors.New("unknown cassette mode " + string(mode))
+	}
+	return t, nil
+}
+
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	recorded, err := t.newRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	if t.mode == Replay {
+		return t.replay(request, recorded)
+	}
+	return t.record(request, recorded)
+}
+
+func (t *Transport) record(request *http.Request, recorded Request) (*http.Response, error) {
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.interactions = append(t.interactions, &Interaction{Request: recorded, Response: newResponse(response, body)})
+	return response, nil
+}
+
+// Saves recorded interactions to cassette file, replayed cassette is not changed
+func (t *Transport) Close() error {
+	if t.mode != Record {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return save(t.path, t.interactions)
+}
+
+func (t *Transport) replay(request *http.Request, recorded Request) (*http.Response, error) {
+	t.mu.Lock()
+	interaction := t.match(recorded)
+	t.mu.Unlock()
+	if interaction == nil {
+		return nil, errors.New("cassette " + t.path + " has no interaction for " + recorded.Method + " " + request.URL.String())
+	}
+
+	body, err := interaction.Response.body()
+	if err != nil {
+		return nil, err
+	}
+	statusCode := interaction.Response.StatusCode
+	return &http.Response{
+		Status:        strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Headers.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}, nil
+}
+
+// Returns first unused interaction of the same path, or of the same template if there is none
+func (t *Transport) match(recorded Request) *Interaction {
+	var samePath, sameTemplate []*Interaction
+	for _, interaction := range t.interactions {
+		r := interaction.Request
+		if len(r.Host) > 0 && (r.Host != recorded.Host || r.BasePath != recorded.BasePath) {
+			continue
+		}
+		if r.Method != recorded.Method || r.PathTemplate != recorded.PathTemplate || r.Query != recorded.Query || r.Body != recorded.Body {
+			continue
+		}
+		if r.Path == recorded.Path {
+			samePath = append(samePath, interaction)
+		} else {
+			sameTemplate = append(sameTemplate, interaction)
+		}
+	}
+
+	candidates := samePath
+	if len(candidates) == 0 {
+		candidates = sameTemplate
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	for _, interaction := range candidates {
+		if !t.used[interaction] {
+			t.used[interaction] = true
+			return interaction
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+func (t *Transport) newRequest(request *http.Request) (Request, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return Request{}, err
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	pathTemplate, basePath := t.getPathTemplate(request.URL.Path)
+	return Request{
+		Host:         request.URL.Host,
+		BasePath:     basePath,
+		Method:       request.Method,
+		PathTemplate: pathTemplate,
+		Path:         request.URL.Path,
+		Query:        normalizeQuery(request.URL.RawQuery),
+		Body:         normalizeBody(body),
+	}, nil
+}
+
+// Returns the most specific template matching path and base path before it, or path itself if no template matches
+func (t *Transport) getPathTemplate(path string) (string, string) {
+	for _, template := range t.templates {
+		if match := template.regexp.FindStringSubmatch(path); match != nil {
+			return template.template, match[1]
+		}
+	}
+	return path, ""
+}
@@ -0,0 +1,280 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	oas_utils "openapi-to-graphql/oas_utils"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+)
+
+// Returns source of REST client with a method of every operation
+func (g *generator) client() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "package %s\n\n", g.packageName)
+
+	imports := []string{"bytes", "context", "fmt", "io/ioutil", "net/http", "net/url", "reflect", "sort", "strings"}
+	for _, operation := range g.operations {
+		switch {
+		case operation.NoContent:
+		case oas_utils.IsBinaryMediaType(operation.ResponseContentType):
+			imports = append(imports, "encoding/base64")
+		case oas_utils.IsXMLMediaType(operation.ResponseContentType):
+			imports = append(imports, "encoding/xml")
+		}
+	}
+	imports = append(imports, "encoding/json")
+	b.WriteString("import (\n")
+	for _, name := range uniqueSorted(imports) {
+		fmt.Fprintf(b, "\t%q\n", name)
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(b, `// Server URL of spec
+const DefaultBaseURL = %q
+
+// REST client of upstream operations
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// Returns client of upstream. DefaultBaseURL is used if baseURL is empty, http.DefaultClient if httpClient is nil
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if len(baseURL) == 0 {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{BaseURL: baseURL, HTTPClient: httpClient}
+}
+
+`, g.serverUrl)
+
+	for _, operation := range g.operations {
+		g.writeClientMethod(b, operation)
+	}
+
+	g.writeClientHelpers(b)
+	return b.String()
+}
+
+func (g *generator) writeClientMethod(b *strings.Builder, operation *types.OperationDefinition) {
+	resultType := g.resultType(operation)
+
+	writeComment(b, "", operation.Description)
+	fmt.Fprintf(b, "func (c *Client) %s(ctx context.Context, args %s) (%s, error) {\n", methodName(operation), argsType(operation), resultType)
+	fmt.Fprintf(b, "\tvar result %s\n", resultType)
+	endpoint := operation.Path
+	// operation with own server has absolute endpoint
+	if operation.ServerUrl != g.serverUrl {
+		endpoint = operation.ServerUrl + operation.Path
+	}
+	fmt.Fprintf(b, "\tendpoint := %q\n", endpoint)
+	b.WriteString("\tquery := []string{}\n")
+
+	args := argNames(operation)
+	names := uniqueNames(args)
+	for _, arg := range args {
+		param, ok := operation.ArgToParam[arg]
+		if !ok || param.Value == nil {
+			continue
+		}
+		field := "args." + names[arg]
+		value := field
+		if isPointer(g.goType(operation.ArgDefinitions[arg])) {
+			value = "*" + field
+		}
+
+		switch param.Value.In {
+		case "query":
+			fmt.Fprintf(b, "\tif !isNil(%s) {\n\t\tquery = append(query, serialize(%s, %q))\n\t}\n", field, value, param.Value.Name)
+		case "path":
+			fmt.Fprintf(b, "\tif !isNil(%s) {\n\t\tendpoint = strings.Replace(endpoint, %q, fmt.Sprint(%s), 1)\n\t}\n", field, "{"+param.Value.Name+"}", value)
+		}
+	}
+
+	body := "nil"
+	contentType := ""
+	if requestBody := operation.RequestBodyDefinition; requestBody != nil && len(requestBody.ArgumentName) > 0 {
+		body = "args." + names[requestBody.ArgumentName]
+		contentType = requestBody.ContentType
+	}
+	results := "_, body, err"
+	if operation.NoContent {
+		results = "_, _, err"
+	} else if oas_utils.IsBinaryMediaType(operation.ResponseContentType) {
+		results = "response, body, err"
+	}
+	fmt.Fprintf(b, "\t%s := c.do(ctx, %q, endpoint, query, %q, %s, %q)\n", results, strings.ToUpper(operation.HttpMethod), contentType, body, operation.ResponseContentType)
+	b.WriteString("\tif err != nil {\n\t\treturn result, err\n\t}\n")
+
+	switch {
+	case operation.NoContent:
+		b.WriteString("\treturn true, nil\n")
+	case oas_utils.IsBinaryMediaType(operation.ResponseContentType):
+		file := `File{
+		ContentType: response.Header.Get("Content-Type"),
+		Size:        len(body),
+		Base64:      base64.StdEncoding.EncodeToString(body),
+	}`
+		if isPointer(resultType) {
+			file = "&" + file
+		}
+		fmt.Fprintf(b, "\treturn %s, nil\n", file)
+	case oas_utils.IsXMLMediaType(operation.ResponseContentType):
+		b.WriteString("\terr = decodeXML(body, &result)\n\treturn result, err\n")
+	case oas_utils.IsJSONMediaType(operation.ResponseContentType) || strings.TrimPrefix(resultType, "*") != "string":
+		b.WriteString("\tif len(body) > 0 {\n\t\terr = json.Unmarshal(body, &result)\n\t}\n\treturn result, err\n")
+	default:
+		// text response
+		b.WriteString("\ttext := string(body)\n")
+		if isPointer(resultType) {
+			b.WriteString("\treturn &text, nil\n")
+		} else {
+			b.WriteString("\treturn text, nil\n")
+		}
+	}
+	b.WriteString("}\n\n")
+}
+
+func (g *generator) writeClientHelpers(b *strings.Builder) {
+	b.WriteString(`// Calls upstream. Returns error if response status is 400 or higher
+func (c *Client) do(ctx context.Context, method string, endpoint string, query []string, contentType string, data interface{}, accept string) (*http.Response, []byte, error) {
+	requestURL := endpoint
+	if strings.HasPrefix(endpoint, "/") {
+		requestURL = c.BaseURL + endpoint
+	}
+	if encodedQuery := url.PathEscape(strings.Join(query, "&")); len(encodedQuery) > 0 {
+		requestURL += "?" + encodedQuery
+	}
+
+	var body []byte
+	if !isNil(data) {
+		switch contentType {
+		case "application/x-www-form-urlencoded":
+			body = []byte(serialize(data, ""))
+		default:
+			encoded, err := json.Marshal(data)
+			if err != nil {
+				return nil, nil, err
+			}
+			body = encoded
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(contentType) > 0 {
+		request.Header.Set("Content-Type", contentType)
+	}
+	if len(accept) > 0 {
+		request.Header.Set("Accept", accept)
+	}
+
+	response, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if response.StatusCode >= 400 {
+		return nil, nil, fmt.Errorf("StatusCode: %v. Status: %v. Response body: %s", response.StatusCode, response.Status, responseBody)
+	}
+	return response, responseBody, nil
+}
+
+// Serializes value to query string, arrays and objects use brackets, e.g. tags[0]=cute&doll[name]=big
+func serialize(value interface{}, key string) string {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		result := []string{}
+		for i := 0; i < v.Len(); i++ {
+			result = append(result, serialize(v.Index(i).Interface(), fmt.Sprintf("%s[%d]", key, i)))
+		}
+		return strings.Join(result, "&")
+	case reflect.Struct:
+		var object map[string]interface{}
+		data, _ := json.Marshal(v.Interface())
+		json.Unmarshal(data, &object)
+		return serialize(object, key)
+	case reflect.Map:
+		keys := []string{}
+		for _, k := range v.MapKeys() {
+			keys = append(keys, fmt.Sprint(k.Interface()))
+		}
+		sort.Strings(keys)
+		result := []string{}
+		for _, k := range keys {
+			result = append(result, serialize(v.MapIndex(reflect.ValueOf(k)).Interface(), key+"["+k+"]"))
+		}
+		return strings.Join(result, "&")
+	}
+	return key + "=" + fmt.Sprint(v.Interface())
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+`)
+
+	if g.hasXML {
+		b.WriteString(`
+// Decodes XML document, root element of array holds items
+func decodeXML(data []byte, target interface{}) error {
+	t := reflect.TypeOf(target).Elem()
+	if t.Kind() != reflect.Slice {
+		return xml.Unmarshal(data, target)
+	}
+	root := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: "Items", Type: t, Tag: ` + "`xml:\",any\"`" + `},
+	}))
+	if err := xml.Unmarshal(data, root.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(target).Elem().Set(root.Elem().Field(0))
+	return nil
+}
+`)
+	}
+}
+
+func isPointer(goType string) bool {
+	return strings.HasPrefix(goType, "*")
+}
+
+func uniqueSorted(values []string) []string {
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !utils.Contains(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -0,0 +1,30 @@
+package codegen
+
+import (
+	"flag"
+	"log"
+
+	"openapi-to-graphql/loader"
+)
+
+// Runs generate command with command line arguments, e.g. generate --path spec.json --out ./api --package api
+func Run(args []string) {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	path := flags.String("path", "oas/1/spec.json", "Path or URL of oas json or yaml spec, - reads stdin")
+	out := flags.String("out", "generated", "Output directory of generated package")
+	packageName := flags.String("package", "generated", "Name of generated package")
+	flags.Parse(args)
+
+	public, err := loader.Load(*path, loader.Options{ExternalRefs: true})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	files, err := Generate(public, *packageName)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := WriteFiles(*out, files); err != nil {
+		log.Fatalln(err)
+	}
+	log.Print("Generated package " + *packageName + " in " + *out)
+}
@@ -0,0 +1,334 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	oas_utils "openapi-to-graphql/oas_utils"
+	typebuilder "openapi-to-graphql/type_builder"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const header = "// Code generated by openapi-to-graphql generate. DO NOT EDIT.\n\n"
+
+type generator struct {
+	packageName string
+	serverUrl   string
+	operations  []*types.OperationDefinition
+
+	// Go types of definitions by GraphQL type name
+	structs map[string]*types.DataDefinition
+	enums   map[string]*types.DataDefinition
+	// GraphQL types by GraphQL type name
+	objects      map[string]*types.DataDefinition
+	inputObjects map[string]*types.DataDefinition
+	unions       map[string]*types.DataDefinition
+
+	usesFile bool
+	usesJSON bool
+	// Arguments are decoded by graphql tags if input objects have renamed properties
+	renamesInput bool
+	// XML tags are added to structs if spec has XML responses
+	hasXML bool
+}
+
+// Returns Go source files of package with types, graphql-go schema, REST client and resolvers calling the client.
+// Generated code is the plain translation, options like error unions or pagination are not supported
+func Generate(public *openapi3.T, packageName string) (map[string][]byte, error) {
+	serverUrl, err := oas_utils.GetServerUrl(public, types.Options{})
+	if err != nil {
+		return nil, err
+	}
+	operations, err := oas_utils.TranslateOperations(public, types.Options{})
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{
+		packageName:  packageName,
+		serverUrl:    serverUrl,
+		operations:   operations,
+		structs:      make(map[string]*types.DataDefinition),
+		enums:        make(map[string]*types.DataDefinition),
+		objects:      make(map[string]*types.DataDefinition),
+		inputObjects: make(map[string]*types.DataDefinition),
+		unions:       make(map[string]*types.DataDefinition),
+	}
+
+	for _, operation := range g.operations {
+		g.collectOutput(operation.ResponseDefinition)
+		for _, def := range operation.ArgDefinitions {
+			g.collectInput(def)
+		}
+		if oas_utils.IsXMLMediaType(operation.ResponseContentType) {
+			g.hasXML = true
+		}
+	}
+
+	sources := map[string]string{
+		"types.go":     g.types(),
+		"schema.go":    g.schema(),
+		"client.go":    g.client(),
+		"resolvers.go": g.resolvers(),
+	}
+
+	files := make(map[string][]byte)
+	for name, source := range sources {
+		formatted, err := format.Source([]byte(header + source))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		files[name] = formatted
+	}
+	return files, nil
+}
+
+// Writes generated files to directory, directory is created if it does not exist
+func WriteFiles(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) collectOutput(def *types.DataDefinition) {
+	if def == nil {
+		return
+	}
+	switch {
+	case isFile(def):
+		g.usesFile = true
+	case def.TargetGraphQLType == types.Object:
+		if g.objects[def.GraphQLTypeName] != nil {
+			return
+		}
+		g.objects[def.GraphQLTypeName] = def
+		g.structs[def.GraphQLTypeName] = def
+		for _, property := range def.ObjectPropertiesDefinitions {
+			g.collectOutput(property)
+		}
+	case def.TargetGraphQLType == types.List:
+		g.collectOutput(def.ListItemDefinitions)
+	case def.TargetGraphQLType == types.Enum:
+		g.enums[def.GraphQLTypeName] = def
+	case def.TargetGraphQLType == types.Union:
+		if g.unions[def.GraphQLTypeName] != nil {
+			return
+		}
+		g.unions[def.GraphQLTypeName] = def
+		for _, member := range def.UnionDefinitions {
+			g.collectOutput(member)
+		}
+	case def.TargetGraphQLType == types.JSON:
+		g.usesJSON = true
+	}
+}
+
+func (g *generator) collectInput(def *types.DataDefinition) {
+	if def == nil {
+		return
+	}
+	switch {
+	case isFile(def):
+	case def.TargetGraphQLType == types.Object:
+		if g.inputObjects[def.GraphQLTypeName] != nil {
+			return
+		}
+		g.inputObjects[def.GraphQLTypeName] = def
+		g.structs[def.GraphQLTypeName] = def
+		for name, property := range def.ObjectPropertiesDefinitions {
+			if typebuilder.GetFieldName(def, name) != name {
+				g.renamesInput = true
+			}
+			g.collectInput(property)
+		}
+	case def.TargetGraphQLType == types.List:
+		g.collectInput(def.ListItemDefinitions)
+	case def.TargetGraphQLType == types.Enum:
+		g.enums[def.GraphQLTypeName] = def
+	case def.TargetGraphQLType == types.Union, def.TargetGraphQLType == types.JSON:
+		// input unions are JSON
+		g.usesJSON = true
+	}
+}
+
+// Returns Go type of definition. Optional values are pointers
+func (g *generator) goType(def *types.DataDefinition) string {
+	if def == nil {
+		return "interface{}"
+	}
+	t := g.goElemType(def)
+	if def.Required || strings.HasPrefix(t, "[]") || t == "interface{}" {
+		return t
+	}
+	return "*" + t
+}
+
+// Returns Go type of definition without pointer
+func (g *generator) goElemType(def *types.DataDefinition) string {
+	switch {
+	case isFile(def):
+		return "File"
+	case def.TargetGraphQLType == types.Object, def.TargetGraphQLType == types.Enum:
+		return goName(def.GraphQLTypeName)
+	case def.TargetGraphQLType == types.List:
+		return "[]" + g.goElemType(def.ListItemDefinitions)
+	case def.TargetGraphQLType == types.String:
+		return "string"
+	case def.TargetGraphQLType == types.Integer:
+		return "int"
+	case def.TargetGraphQLType == types.Float:
+		return "float64"
+	case def.TargetGraphQLType == types.Boolean:
+		return "bool"
+	}
+	return "interface{}"
+}
+
+// Returns expression of GraphQL type of definition in NewSchema
+func (g *generator) graphQLType(def *types.DataDefinition, input bool) string {
+	var t string
+	switch {
+	case isFile(def) && input:
+		t = "graphql.String"
+	case isFile(def):
+		t = "fileType"
+	case def.TargetGraphQLType == types.Object && input:
+		t = inputObjectVar(def)
+	case def.TargetGraphQLType == types.Object:
+		t = objectVar(def)
+	case def.TargetGraphQLType == types.List:
+		t = "graphql.NewList(" + g.graphQLType(def.ListItemDefinitions, input) + ")"
+	case def.TargetGraphQLType == types.Enum:
+		t = enumVar(def)
+	case def.TargetGraphQLType == types.Union && !input:
+		t = unionVar(def)
+	case def.TargetGraphQLType == types.String:
+		t = "graphql.String"
+	case def.TargetGraphQLType == types.Integer:
+		t = "graphql.Int"
+	case def.TargetGraphQLType == types.Float:
+		t = "graphql.Float"
+	case def.TargetGraphQLType == types.Boolean:
+		t = "graphql.Boolean"
+	default:
+		t = "jsonScalar"
+	}
+	if def.Required {
+		return "graphql.NewNonNull(" + t + ")"
+	}
+	return t
+}
+
+func isFile(def *types.DataDefinition) bool {
+	return def.GraphQLObject != nil && def.GraphQLObject == typebuilder.FileObject
+}
+
+func objectVar(def *types.DataDefinition) string {
+	return utils.LowerFirst(goName(def.GraphQLTypeName)) + "Type"
+}
+
+func inputObjectVar(def *types.DataDefinition) string {
+	return utils.LowerFirst(goName(def.GraphQLTypeName)) + "InputType"
+}
+
+func enumVar(def *types.DataDefinition) string {
+	return utils.LowerFirst(goName(def.GraphQLTypeName)) + "Enum"
+}
+
+func unionVar(def *types.DataDefinition) string {
+	return utils.LowerFirst(goName(def.GraphQLTypeName)) + "Union"
+}
+
+// Returns exported Go identifier, e.g. PetId for pet-id
+func goName(s string) string {
+	words := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, s)
+	name := utils.ToPascalCase(words)
+	if len(name) == 0 || unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+	return name
+}
+
+// Returns Go names of object properties sorted by property name. Duplicate names get number suffix
+func propertyNames(def *types.DataDefinition) ([]string, map[string]string) {
+	properties := make([]string, 0, len(def.ObjectPropertiesDefinitions))
+	for property := range def.ObjectPropertiesDefinitions {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	return properties, uniqueNames(properties)
+}
+
+func uniqueNames(names []string) map[string]string {
+	used := make(map[string]bool)
+	result := make(map[string]string)
+	for _, name := range names {
+		goIdentifier := goName(name)
+		for i := 2; used[goIdentifier]; i++ {
+			goIdentifier = goName(name) + fmt.Sprint(i)
+		}
+		used[goIdentifier] = true
+		result[name] = goIdentifier
+	}
+	return result
+}
+
+func sortedKeys(defs map[string]*types.DataDefinition) []string {
+	keys := make([]string, 0, len(defs))
+	for key := range defs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Returns names of operation arguments sorted
+func argNames(operation *types.OperationDefinition) []string {
+	return sortedKeys(operation.ArgDefinitions)
+}
+
+func methodName(operation *types.OperationDefinition) string {
+	return goName(operation.OperationName)
+}
+
+func argsType(operation *types.OperationDefinition) string {
+	return methodName(operation) + "Args"
+}
+
+// Returns Go type of operation result. Operations without response body return true
+func (g *generator) resultType(operation *types.OperationDefinition) string {
+	if operation.NoContent {
+		return "bool"
+	}
+	return g.goType(operation.ResponseDefinition)
+}
+
+// Writes doc comment with description, multiline descriptions are prefixed on every line
+func writeComment(b *strings.Builder, indent string, text string) {
+	text = strings.TrimSpace(text)
+	if len(text) == 0 {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(indent + strings.TrimRight("// "+line, " ") + "\n")
+	}
+}
@@ -0,0 +1,36 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Returns source of Resolvers interface and its implementation calling client
+func (g *generator) resolvers() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "package %s\n\n", g.packageName)
+	b.WriteString("import \"context\"\n\n")
+
+	b.WriteString("// Resolvers of root fields\ntype Resolvers interface {\n")
+	for _, operation := range g.operations {
+		writeComment(b, "\t", operation.Description)
+		fmt.Fprintf(b, "\t%s(ctx context.Context, args %s) (%s, error)\n", methodName(operation), argsType(operation), g.resultType(operation))
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString(`// Resolves root fields by calling upstream. Embed it to override some of the resolvers
+type ClientResolvers struct {
+	Client *Client
+}
+
+func NewClientResolvers(client *Client) *ClientResolvers {
+	return &ClientResolvers{Client: client}
+}
+
+`)
+	for _, operation := range g.operations {
+		name := methodName(operation)
+		fmt.Fprintf(b, "func (r *ClientResolvers) %s(ctx context.Context, args %s) (%s, error) {\n\treturn r.Client.%s(ctx, args)\n}\n\n", name, argsType(operation), g.resultType(operation), name)
+	}
+	return b.String()
+}
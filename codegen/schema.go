@@ -0,0 +1,351 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	typebuilder "openapi-to-graphql/type_builder"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Returns source of NewSchema with GraphQL types and root fields calling resolvers
+func (g *generator) schema() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "package %s\n\n", g.packageName)
+	b.WriteString(`import (
+	"context"
+	"encoding/json"
+`)
+	if g.renamesInput {
+		b.WriteString("\t\"reflect\"\n\t\"strings\"\n")
+	}
+	b.WriteString(`
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+`)
+
+	b.WriteString("// Returns schema of root fields resolved by resolvers\n")
+	b.WriteString("func NewSchema(resolvers Resolvers) (graphql.Schema, error) {\n")
+
+	// variables are declared first, object fields refer to each other in thunks
+	declarations := make([]string, 0)
+	for _, name := range sortedKeys(g.enums) {
+		declarations = append(declarations, enumVar(g.enums[name])+" *graphql.Enum")
+	}
+	for _, name := range sortedKeys(g.objects) {
+		declarations = append(declarations, objectVar(g.objects[name])+" *graphql.Object")
+	}
+	for _, name := range sortedKeys(g.inputObjects) {
+		declarations = append(declarations, inputObjectVar(g.inputObjects[name])+" *graphql.InputObject")
+	}
+	for _, name := range sortedKeys(g.unions) {
+		declarations = append(declarations, unionVar(g.unions[name])+" *graphql.Union")
+	}
+	if len(declarations) > 0 {
+		b.WriteString("\tvar (\n")
+		for _, declaration := range declarations {
+			b.WriteString("\t\t" + declaration + "\n")
+		}
+		b.WriteString("\t)\n\n")
+	}
+
+	for _, name := range sortedKeys(g.enums) {
+		g.writeEnumType(b, g.enums[name])
+	}
+	for _, name := range sortedKeys(g.objects) {
+		g.writeObjectType(b, g.objects[name])
+	}
+	for _, name := range sortedKeys(g.inputObjects) {
+		g.writeInputObjectType(b, g.inputObjects[name])
+	}
+	// unions are created after member objects
+	for _, name := range sortedKeys(g.unions) {
+		g.writeUnionType(b, g.unions[name])
+	}
+
+	config := make([]string, 0)
+	for _, root := range []struct {
+		name          string
+		operationType int
+	}{{"Query", types.Query}, {"Mutation", types.Mutation}} {
+		operations := make([]*types.OperationDefinition, 0)
+		for _, operation := range g.operations {
+			if operation.OperationType == root.operationType {
+				operations = append(operations, operation)
+			}
+		}
+		if len(operations) == 0 {
+			continue
+		}
+		variable := strings.ToLower(root.name) + "Type"
+		fmt.Fprintf(b, "\t%s := graphql.NewObject(graphql.ObjectConfig{\n\t\tName: %q,\n\t\tFields: graphql.Fields{\n", variable, root.name)
+		for _, operation := range operations {
+			g.writeRootField(b, operation)
+		}
+		b.WriteString("\t\t},\n\t})\n\n")
+		config = append(config, root.name+": "+variable+",")
+	}
+
+	b.WriteString("\treturn graphql.NewSchema(graphql.SchemaConfig{\n")
+	for _, line := range config {
+		b.WriteString("\t\t" + line + "\n")
+	}
+	b.WriteString("\t})\n}\n\n")
+
+	g.writeSchemaHelpers(b)
+	return b.String()
+}
+
+func (g *generator) writeEnumType(b *strings.Builder, def *types.DataDefinition) {
+	fmt.Fprintf(b, "\t%s = graphql.NewEnum(graphql.EnumConfig{\n\t\tName: %q,\n\t\tValues: graphql.EnumValueConfigMap{\n", enumVar(def), def.GraphQLTypeName)
+	constNames := enumConstNames(def)
+	for _, value := range enumValues(def) {
+		literal := constNames[utils.EnumValueString(value)]
+		if enumGoType(def) == "interface{}" {
+			// values of enum without Go type are decoded from JSON
+			literal = goLiteral(value)
+			if _, ok := value.(float64); ok {
+				literal = "float64(" + literal + ")"
+			}
+		}
+		enumValue := getEnumValue(def, value)
+		fmt.Fprintf(b, "\t\t\t%q: &graphql.EnumValueConfig{Value: %s", enumValue.Name, literal)
+		if len(enumValue.Description) > 0 {
+			fmt.Fprintf(b, ", Description: %q", enumValue.Description)
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("\t\t},\n\t})\n\n")
+}
+
+// Returns enum value named by namer in translation
+func getEnumValue(def *types.DataDefinition, value interface{}) *graphql.EnumValueDefinition {
+	if enum, ok := graphql.GetNullable(def.GraphQLType).(*graphql.Enum); ok {
+		for _, enumValue := range enum.Values() {
+			if enumValue.Value == value {
+				return enumValue
+			}
+		}
+	}
+	return &graphql.EnumValueDefinition{Name: strings.ToUpper(utils.EnumValueString(value)), Value: value}
+}
+
+func (g *generator) writeObjectType(b *strings.Builder, def *types.DataDefinition) {
+	fmt.Fprintf(b, "\t%s = graphql.NewObject(graphql.ObjectConfig{\n\t\tName: %q,\n", objectVar(def), def.GraphQLTypeName)
+	b.WriteString("\t\tFields: graphql.FieldsThunk(func() graphql.Fields {\n\t\t\treturn graphql.Fields{\n")
+	properties, _ := propertyNames(def)
+	for _, property := range properties {
+		fmt.Fprintf(b, "\t\t\t\t%q: &graphql.Field{Type: %s},\n", typebuilder.GetFieldName(def, property), g.graphQLType(def.ObjectPropertiesDefinitions[property], false))
+	}
+	b.WriteString("\t\t\t}\n\t\t}),\n\t})\n\n")
+}
+
+func (g *generator) writeInputObjectType(b *strings.Builder, def *types.DataDefinition) {
+	fmt.Fprintf(b, "\t%s = graphql.NewInputObject(graphql.InputObjectConfig{\n\t\tName: %q,\n", inputObjectVar(def), def.GraphQLInputTypeName)
+	b.WriteString("\t\tFields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {\n\t\t\treturn graphql.InputObjectConfigFieldMap{\n")
+	properties, _ := propertyNames(def)
+	for _, property := range properties {
+		fmt.Fprintf(b, "\t\t\t\t%q: &graphql.InputObjectFieldConfig{Type: %s},\n", typebuilder.GetFieldName(def, property), g.graphQLType(def.ObjectPropertiesDefinitions[property], true))
+	}
+	b.WriteString("\t\t\t}\n\t\t}),\n\t})\n\n")
+}
+
+// Union member is resolved by properties of value like in runtime schema
+func (g *generator) writeUnionType(b *strings.Builder, def *types.DataDefinition) {
+	members := make([]string, 0, len(def.UnionDefinitions))
+	for _, member := range def.UnionDefinitions {
+		members = append(members, objectVar(member))
+	}
+	fmt.Fprintf(b, "\t%s = graphql.NewUnion(graphql.UnionConfig{\n\t\tName: %q,\n", unionVar(def), def.GraphQLTypeName)
+	fmt.Fprintf(b, "\t\tTypes: []*graphql.Object{%s},\n", strings.Join(members, ", "))
+	b.WriteString("\t\tResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {\n")
+	b.WriteString("\t\t\tvalue, _ := p.Value.(map[string]interface{})\n")
+	for _, member := range def.UnionDefinitions {
+		properties, _ := propertyNames(member)
+		quoted := make([]string, 0, len(properties))
+		for _, property := range properties {
+			quoted = append(quoted, strconv.Quote(property))
+		}
+		fmt.Fprintf(b, "\t\t\tif hasProperties(value, %s) {\n\t\t\t\treturn %s\n\t\t\t}\n", strings.Join(quoted, ", "), objectVar(member))
+	}
+	b.WriteString("\t\t\treturn nil\n\t\t},\n\t})\n\n")
+}
+
+func (g *generator) writeRootField(b *strings.Builder, operation *types.OperationDefinition) {
+	resultType := "graphql.Boolean"
+	if !operation.NoContent {
+		resultType = g.graphQLType(operation.ResponseDefinition, false)
+	}
+
+	fmt.Fprintf(b, "\t\t\t%q: &graphql.Field{\n", operation.OperationName)
+	if len(operation.Description) > 0 {
+		fmt.Fprintf(b, "\t\t\t\tDescription: %q,\n", operation.Description)
+	}
+	fmt.Fprintf(b, "\t\t\t\tType: %s,\n", resultType)
+
+	args := argNames(operation)
+	if len(args) > 0 {
+		b.WriteString("\t\t\t\tArgs: graphql.FieldConfigArgument{\n")
+		for _, arg := range args {
+			fmt.Fprintf(b, "\t\t\t\t\t%q: &graphql.ArgumentConfig{Type: %s", arg, g.graphQLType(operation.ArgDefinitions[arg], true))
+			if description := argDescription(operation, arg); len(description) > 0 {
+				fmt.Fprintf(b, ", Description: %q", description)
+			}
+			b.WriteString("},\n")
+		}
+		b.WriteString("\t\t\t\t},\n")
+	}
+
+	fmt.Fprintf(b, `				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var args %s
+					if err := decodeArgs(p.Args, &args); err != nil {
+						return nil, err
+					}
+					result, err := resolvers.%s(contextOf(p), args)
+					if err != nil {
+						return nil, err
+					}
+					return result, nil
+				},
+			},
+`, argsType(operation), methodName(operation))
+}
+
+func argDescription(operation *types.OperationDefinition, arg string) string {
+	if param, ok := operation.ArgToParam[arg]; ok && param.Value != nil {
+		return param.Value.Description
+	}
+	return ""
+}
+
+func (g *generator) writeSchemaHelpers(b *strings.Builder) {
+	if g.usesFile {
+		b.WriteString(`var fileType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "File",
+	Description: "Binary response, e.g. application/octet-stream or image/png",
+	Fields: graphql.Fields{
+		"contentType": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"size":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"base64":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"url":         &graphql.Field{Type: graphql.String},
+	},
+})
+
+`)
+	}
+
+	b.WriteString(`var jsonScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "JSON",
+	Description:  "The ` + "`JSON`" + ` scalar type represents JSON values as specified by [ECMA-404](http://www.ecma-international.org/publications/files/ECMA-ST/ECMA-404.pdf)",
+	Serialize:    func(value interface{}) interface{} { return value },
+	ParseValue:   func(value interface{}) interface{} { return value },
+	ParseLiteral: parseLiteral,
+})
+
+func parseLiteral(value ast.Value) interface{} {
+	switch value := value.(type) {
+	case *ast.ObjectValue:
+		object := make(map[string]interface{})
+		for _, field := range value.Fields {
+			object[field.Name.Value] = parseLiteral(field.Value)
+		}
+		return object
+	case *ast.ListValue:
+		list := make([]interface{}, 0, len(value.Values))
+		for _, item := range value.Values {
+			list = append(list, parseLiteral(item))
+		}
+		return list
+	case *ast.StringValue, *ast.BooleanValue, *ast.IntValue, *ast.FloatValue:
+		return value.GetValue()
+	}
+	return nil
+}
+
+func contextOf(p graphql.ResolveParams) context.Context {
+	if p.Context == nil {
+		return context.Background()
+	}
+	return p.Context
+}
+`)
+
+	if g.renamesInput {
+		b.WriteString(`
+// Decodes field arguments to arguments struct, renamed fields are decoded to original properties
+func decodeArgs(args map[string]interface{}, target interface{}) error {
+	data, err := json.Marshal(renameFields(args, reflect.TypeOf(target)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// Returns value with JSON names of struct fields instead of names of graphql tags
+func renameFields(value interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch value := value.(type) {
+	case []interface{}:
+		if t.Kind() != reflect.Slice {
+			return value
+		}
+		result := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			result = append(result, renameFields(item, t.Elem()))
+		}
+		return result
+	case map[string]interface{}:
+		if t.Kind() != reflect.Struct {
+			return value
+		}
+		result := make(map[string]interface{}, len(value))
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			fieldName := name
+			if tag := field.Tag.Get("graphql"); len(tag) > 0 {
+				fieldName = tag
+			}
+			if fieldValue, ok := value[fieldName]; ok {
+				result[name] = renameFields(fieldValue, field.Type)
+			}
+		}
+		return result
+	}
+	return value
+}
+`)
+	} else {
+		b.WriteString(`
+// Decodes field arguments to arguments struct
+func decodeArgs(args map[string]interface{}, target interface{}) error {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+`)
+	}
+
+	if len(g.unions) > 0 {
+		b.WriteString(`
+func hasProperties(value map[string]interface{}, properties ...string) bool {
+	for _, property := range properties {
+		if _, ok := value[property]; !ok {
+			return false
+		}
+	}
+	return true
+}
+`)
+	}
+}
@@ -0,0 +1,206 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"openapi-to-graphql/naming"
+	oas_utils "openapi-to-graphql/oas_utils"
+	typebuilder "openapi-to-graphql/type_builder"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+)
+
+// Returns source of structs of objects and arguments, and string types of enums
+func (g *generator) types() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "package %s\n\n", g.packageName)
+
+	for _, name := range sortedKeys(g.structs) {
+		g.writeStruct(b, g.structs[name])
+	}
+	for _, name := range sortedKeys(g.enums) {
+		g.writeEnum(b, g.enums[name])
+	}
+	if g.usesFile {
+		b.WriteString(`// Binary response
+type File struct {
+	ContentType string  ` + "`json:\"contentType\"`" + `
+	Size        int     ` + "`json:\"size\"`" + `
+	Base64      string  ` + "`json:\"base64\"`" + `
+	Url         *string ` + "`json:\"url\"`" + `
+}
+
+`)
+	}
+	for _, operation := range g.operations {
+		g.writeArgs(b, operation)
+	}
+	return b.String()
+}
+
+func (g *generator) writeStruct(b *strings.Builder, def *types.DataDefinition) {
+	writeComment(b, "", def.Schema.Description)
+	fmt.Fprintf(b, "type %s struct {\n", goName(def.GraphQLTypeName))
+	properties, names := propertyNames(def)
+	for _, property := range properties {
+		propertyDef := def.ObjectPropertiesDefinitions[property]
+		tag := "json:" + strconv.Quote(jsonTag(property, propertyDef))
+		if g.hasXML {
+			tag += " xml:" + strconv.Quote(xmlTag(property, propertyDef))
+		}
+		// default resolver of graphql-go finds renamed field by graphql tag
+		if fieldName := typebuilder.GetFieldName(def, property); fieldName != property {
+			tag += " graphql:" + strconv.Quote(fieldName)
+		}
+		writeComment(b, "\t", propertyDef.Schema.Description)
+		fmt.Fprintf(b, "\t%s %s `%s`\n", names[property], g.goType(propertyDef), tag)
+	}
+	b.WriteString("}\n\n")
+}
+
+func (g *generator) writeEnum(b *strings.Builder, def *types.DataDefinition) {
+	goType := enumGoType(def)
+	if goType == "interface{}" {
+		return
+	}
+	name := goName(def.GraphQLTypeName)
+	writeComment(b, "", def.Schema.Description)
+	fmt.Fprintf(b, "type %s %s\n\n", name, goType)
+	values := enumValues(def)
+	if len(values) == 0 {
+		return
+	}
+	constNames := enumConstNames(def)
+	b.WriteString("const (\n")
+	for _, value := range values {
+		fmt.Fprintf(b, "\t%s %s = %s\n", constNames[utils.EnumValueString(value)], name, goLiteral(value))
+	}
+	b.WriteString(")\n\n")
+}
+
+func (g *generator) writeArgs(b *strings.Builder, operation *types.OperationDefinition) {
+	fmt.Fprintf(b, "// Arguments of %s\n", operation.OperationName)
+	fmt.Fprintf(b, "type %s struct {\n", argsType(operation))
+	args := argNames(operation)
+	names := uniqueNames(args)
+	for _, arg := range args {
+		def := operation.ArgDefinitions[arg]
+		fmt.Fprintf(b, "\t%s %s `json:%s`\n", names[arg], g.goType(def), strconv.Quote(jsonTag(arg, def)))
+	}
+	b.WriteString("}\n\n")
+}
+
+// Returns values of enum like GraphQL enum has them, null is not a value
+func enumValues(def *types.DataDefinition) []interface{} {
+	values := make([]interface{}, 0, len(def.Schema.Enum))
+	seen := make([]string, 0, len(def.Schema.Enum))
+	for _, value := range def.Schema.Enum {
+		if key := utils.EnumValueString(value); value != nil && !utils.Contains(seen, key) {
+			values = append(values, value)
+			seen = append(seen, key)
+		}
+	}
+	return values
+}
+
+// Returns Go type of enum values, interface{} if values have different types
+func enumGoType(def *types.DataDefinition) string {
+	goType := ""
+	for _, value := range enumValues(def) {
+		t := "interface{}"
+		switch v := value.(type) {
+		case string:
+			t = "string"
+		case bool:
+			t = "bool"
+		case float64:
+			t = "float64"
+			if def.Schema.Type == "integer" && v == float64(int(v)) {
+				t = "int"
+			}
+		}
+		if len(goType) > 0 && goType != t {
+			return "interface{}"
+		}
+		goType = t
+	}
+	if len(goType) == 0 {
+		return "string"
+	}
+	return goType
+}
+
+// Returns names of enum constants by value string, e.g. SortAsc. Names of x-enum-varnames are preferred
+func enumConstNames(def *types.DataDefinition) map[string]string {
+	var varNames []string
+	utils.GetExtension(def.Schema.Extensions, naming.EnumVarNamesExtension, &varNames)
+
+	keys := make([]string, 0)
+	names := make([]string, 0)
+	for i, value := range def.Schema.Enum {
+		if value == nil {
+			continue
+		}
+		key := utils.EnumValueString(value)
+		name := key
+		if i < len(varNames) && len(varNames[i]) > 0 {
+			name = varNames[i]
+		}
+		keys = append(keys, key)
+		names = append(names, name)
+	}
+
+	goNames := uniqueNames(names)
+	prefix := goName(def.GraphQLTypeName)
+	result := make(map[string]string)
+	for i, key := range keys {
+		if _, ok := result[key]; !ok {
+			result[key] = prefix + goNames[names[i]]
+		}
+	}
+	return result
+}
+
+// Returns Go literal of JSON value
+func goLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return utils.EnumValueString(value)
+}
+
+func jsonTag(name string, def *types.DataDefinition) string {
+	if def.Required {
+		return name
+	}
+	return name + ",omitempty"
+}
+
+// Returns tag of schema xml object: name, attribute and wrapped arrays, e.g. tags>tag
+func xmlTag(name string, def *types.DataDefinition) string {
+	xmlObject := oas_utils.GetXMLObject(def.Schema)
+	if len(xmlObject.Name) > 0 {
+		name = xmlObject.Name
+	}
+	if xmlObject.Attribute {
+		return name + ",attr"
+	}
+	if def.TargetGraphQLType == types.List && def.ListItemDefinitions != nil {
+		itemName := oas_utils.GetXMLObject(def.ListItemDefinitions.Schema).Name
+		if xmlObject.Wrapped {
+			if len(itemName) == 0 {
+				itemName = name
+			}
+			return name + ">" + itemName
+		}
+		if len(itemName) > 0 {
+			return itemName
+		}
+	}
+	return name
+}
@@ -0,0 +1,102 @@
+package federation
+
+import (
+	"fmt"
+
+	typebuilder "openapi-to-graphql/type_builder"
+
+	"github.com/graphql-go/graphql"
+)
+
+// URL of Federation v2 spec linked by SDL of subgraph
+const SpecURL = "https://specs.apollo.dev/federation/v2.0"
+
+// Object type with @key directive. Representations are resolved by Resolve, entity without Resolve is not resolvable by subgraph
+type Entity struct {
+	Object  *graphql.Object
+	Fields  string
+	Resolve func(p graphql.ResolveParams, representation map[string]interface{}) (interface{}, error)
+}
+
+var anyScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "_Any",
+	Serialize:    func(value interface{}) interface{} { return value },
+	ParseValue:   func(value interface{}) interface{} { return value },
+	ParseLiteral: typebuilder.JSONScalar.ParseLiteral,
+})
+
+var serviceObject = graphql.NewObject(graphql.ObjectConfig{
+	Name: "_Service",
+	Fields: graphql.Fields{
+		"sdl": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+// Returns _service and _entities query fields of subgraph. _entities is omitted if there are no entities
+func CreateFields(entities []*Entity) graphql.Fields {
+	fields := graphql.Fields{
+		"_service": &graphql.Field{
+			Type: graphql.NewNonNull(serviceObject),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return map[string]interface{}{"sdl": PrintSchema(p.Info.Schema, entities)}, nil
+			},
+		},
+	}
+
+	byName := make(map[string]*Entity)
+	objects := make([]*graphql.Object, 0, len(entities))
+	for _, entity := range entities {
+		byName[entity.Object.Name()] = entity
+		objects = append(objects, entity.Object)
+	}
+	if len(objects) == 0 {
+		return fields
+	}
+
+	entityUnion := graphql.NewUnion(graphql.UnionConfig{
+		Name:  "_Entity",
+		Types: objects,
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			value, _ := p.Value.(map[string]interface{})
+			if entity, ok := byName[fmt.Sprint(value["__typename"])]; ok {
+				return entity.Object
+			}
+			return nil
+		},
+	})
+
+	fields["_entities"] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(entityUnion)),
+		Args: graphql.FieldConfigArgument{
+			"representations": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(anyScalar))),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			representations, _ := p.Args["representations"].([]interface{})
+			result := make([]interface{}, 0, len(representations))
+			for _, r := range representations {
+				representation, ok := r.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("Representation is not an object: %v", r)
+				}
+				typeName := fmt.Sprint(representation["__typename"])
+				entity, ok := byName[typeName]
+				if !ok || entity.Resolve == nil {
+					return nil, fmt.Errorf("Entity %s is not resolvable by subgraph", typeName)
+				}
+				value, err := entity.Resolve(p, representation)
+				if err != nil {
+					return nil, err
+				}
+				// union member is resolved by __typename
+				if object, ok := value.(map[string]interface{}); ok {
+					object["__typename"] = typeName
+				}
+				result = append(result, value)
+			}
+			return result, nil
+		},
+	}
+	return fields
+}
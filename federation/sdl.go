@@ -0,0 +1,226 @@
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"openapi-to-graphql/utils"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Types and fields added by federation are not part of subgraph SDL
+var federationTypes = []string{"_Any", "_Entity", "_Service"}
+var federationFields = []string{"_service", "_entities"}
+
+var builtInScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+// Returns SDL of schema with @key directives of entities
+func PrintSchema(schema graphql.Schema, entities []*Entity) string {
+	keys := make(map[string]*Entity)
+	for _, entity := range entities {
+		keys[entity.Object.Name()] = entity
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "extend schema @link(url: %q, import: [\"@key\"])\n", SpecURL)
+
+	typeMap := schema.TypeMap()
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		if strings.HasPrefix(name, "__") || utils.Contains(federationTypes, name) || utils.Contains(builtInScalars, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b.WriteString("\n")
+		switch t := typeMap[name].(type) {
+		case *graphql.Object:
+			// Description() of object is always empty in graphql-go
+			printDescription(b, "", t.PrivateDescription)
+			b.WriteString("type " + name)
+			if entity, ok := keys[name]; ok {
+				printKey(b, entity)
+			}
+			b.WriteString(" {\n")
+			printFields(b, t.Fields())
+			b.WriteString("}\n")
+		case *graphql.Interface:
+			printDescription(b, "", t.Description())
+			b.WriteString("interface " + name + " {\n")
+			printFields(b, t.Fields())
+			b.WriteString("}\n")
+		case *graphql.InputObject:
+			printDescription(b, "", t.Description())
+			b.WriteString("input " + name + " {\n")
+			fields := t.Fields()
+			for _, fieldName := range sortedKeys(fields) {
+				field := fields[fieldName]
+				printDescription(b, "  ", field.Description())
+				b.WriteString("  " + fieldName + ": " + field.Type.String())
+				printDefaultValue(b, field.DefaultValue)
+				b.WriteString("\n")
+			}
+			b.WriteString("}\n")
+		case *graphql.Union:
+			printDescription(b, "", t.Description())
+			members := make([]string, 0, len(t.Types()))
+			for _, member := range t.Types() {
+				members = append(members, member.Name())
+			}
+			b.WriteString("union " + name + " = " + strings.Join(members, " | ") + "\n")
+		case *graphql.Enum:
+			printDescription(b, "", t.Description())
+			b.WriteString("enum " + name + " {\n")
+			values := t.Values()
+			sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+			for _, value := range values {
+				printDescription(b, "  ", value.Description)
+				b.WriteString("  " + value.Name)
+				printDeprecated(b, value.DeprecationReason)
+				b.WriteString("\n")
+			}
+			b.WriteString("}\n")
+		case *graphql.Scalar:
+			printDescription(b, "", t.Description())
+			b.WriteString("scalar " + name + "\n")
+		}
+	}
+	return b.String()
+}
+
+func printKey(b *strings.Builder, entity *Entity) {
+	fmt.Fprintf(b, " @key(fields: %q", entity.Fields)
+	if entity.Resolve == nil {
+		b.WriteString(", resolvable: false")
+	}
+	b.WriteString(")")
+}
+
+func printFields(b *strings.Builder, fields graphql.FieldDefinitionMap) {
+	for _, name := range sortedKeys(fields) {
+		if utils.Contains(federationFields, name) {
+			continue
+		}
+		field := fields[name]
+		printDescription(b, "  ", field.Description)
+		b.WriteString("  " + name)
+		printArgs(b, field.Args)
+		b.WriteString(": " + field.Type.String())
+		printDeprecated(b, field.DeprecationReason)
+		b.WriteString("\n")
+	}
+}
+
+// Arguments with descriptions are printed on separate lines
+func printArgs(b *strings.Builder, args []*graphql.Argument) {
+	if len(args) == 0 {
+		return
+	}
+	args = append([]*graphql.Argument{}, args...)
+	sort.Slice(args, func(i, j int) bool { return args[i].Name() < args[j].Name() })
+
+	multiline := false
+	for _, arg := range args {
+		if len(arg.Description()) > 0 {
+			multiline = true
+		}
+	}
+
+	b.WriteString("(")
+	for i, arg := range args {
+		if multiline {
+			b.WriteString("\n")
+			printDescription(b, "    ", arg.Description())
+			b.WriteString("    ")
+		} else if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(arg.Name() + ": " + arg.Type.String())
+		printDefaultValue(b, arg.DefaultValue)
+	}
+	if multiline {
+		b.WriteString("\n  ")
+	}
+	b.WriteString(")")
+}
+
+func printDescription(b *strings.Builder, indent string, description string) {
+	if len(description) == 0 {
+		return
+	}
+	if !strings.Contains(description, "\n") {
+		b.WriteString(indent + strconv.Quote(description) + "\n")
+		return
+	}
+	b.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(strings.ReplaceAll(description, `"""`, `\"""`), "\n") {
+		b.WriteString(indent + line + "\n")
+	}
+	b.WriteString(indent + `"""` + "\n")
+}
+
+func printDeprecated(b *strings.Builder, reason string) {
+	if len(reason) > 0 {
+		fmt.Fprintf(b, " @deprecated(reason: %q)", reason)
+	}
+}
+
+func printDefaultValue(b *strings.Builder, value interface{}) {
+	if value == nil {
+		return
+	}
+	b.WriteString(" = " + printValue(value))
+}
+
+// Returns GraphQL literal of value, object keys are not quoted
+func printValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, printValue(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fields := make([]string, 0, len(v))
+		for _, key := range keys {
+			fields = append(fields, key+": "+printValue(v[key]))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	return string(data)
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := make([]string, 0)
+	switch m := m.(type) {
+	case graphql.FieldDefinitionMap:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case graphql.InputObjectFieldMap:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
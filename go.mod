@@ -6,7 +6,14 @@ require (
 	github.com/gertd/go-pluralize v0.1.7
 	github.com/getkin/kin-openapi v0.68.0
 	github.com/gin-gonic/gin v1.7.4
+	github.com/gorilla/websocket v1.4.2
 	github.com/graphql-go/graphql v0.7.9
 	github.com/graphql-go/handler v0.2.3
 	github.com/jinzhu/copier v0.3.2
+	github.com/prometheus/client_golang v1.11.1
+	go.opentelemetry.io/otel v1.3.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.3.0
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.3.0
+	go.opentelemetry.io/otel/sdk v1.3.0
+	go.opentelemetry.io/otel/trace v1.3.0
 )
@@ -0,0 +1,196 @@
+package loader
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Timeout of loading spec or ref from URL if Options.Client is not set
+const DefaultTimeout = 30 * time.Second
+
+// Options of spec loading. JSON and YAML specs are supported
+type Options struct {
+	// Allow $refs to other files and URLs. Refs are read only from RefRoots
+	ExternalRefs bool
+	// Directories and URL prefixes external refs are read from, e.g. ./specs or https://example.com/specs/.
+	// Directory or URL directory of spec if empty
+	RefRoots []string
+	// Directory of local copies of specs and refs loaded from URLs, used when URL can't be fetched.
+	// Copy of https://example.com/specs/pets.yaml is <FallbackDir>/example.com/specs/pets.yaml
+	FallbackDir string
+	// Client of URLs, client with DefaultTimeout if not set
+	Client *http.Client
+	// Spec of location -, os.Stdin if not set
+	Stdin io.Reader
+	// Unknown formats of schemas fail validation, e.g. uuid. OpenAPI allows any format
+	StrictFormats bool
+}
+
+type reader struct {
+	options Options
+	client  *http.Client
+	roots   []string
+	// URL or absolute path of spec, spec is read even if it is not in roots
+	location string
+}
+
+// Returns true if location is http or https URL
+func IsURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// Loads and validates spec from file, http(s) URL or stdin if location is -
+func Load(location string, options Options) (*openapi3.T, error) {
+	r := &reader{options: options, client: options.Client}
+	if r.client == nil {
+		r.client = &http.Client{Timeout: DefaultTimeout}
+	}
+	roots, err := getRefRoots(location, options.RefRoots)
+	if err != nil {
+		return nil, err
+	}
+	r.roots = roots
+	r.location = location
+	if !IsURL(location) {
+		if r.location, err = filepath.Abs(location); err != nil {
+			return nil, err
+		}
+	}
+
+	kinLoader := openapi3.NewLoader()
+	kinLoader.IsExternalRefsAllowed = options.ExternalRefs
+	kinLoader.ReadFromURIFunc = r.read
+
+	var doc *openapi3.T
+	switch {
+	case location == "-":
+		stdin := options.Stdin
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+		var data []byte
+		if data, err = ioutil.ReadAll(stdin); err != nil {
+			return nil, err
+		}
+		// relative refs of stdin spec are resolved against working directory
+		doc, err = kinLoader.LoadFromDataWithPath(data, &url.URL{Path: filepath.ToSlash(filepath.Join(roots[0], "stdin"))})
+	case IsURL(location):
+		var specURL *url.URL
+		specURL, err = url.Parse(location)
+		if err == nil {
+			doc, err = kinLoader.LoadFromURI(specURL)
+		}
+	default:
+		doc, err = kinLoader.LoadFromFile(location)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Loading spec %s failed: %v", location, err)
+	}
+
+	if err := Validate(doc, location, options.StrictFormats); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Returns absolute directories and URL prefixes of refs. Directory of spec if roots are empty
+func getRefRoots(location string, roots []string) ([]string, error) {
+	if len(roots) == 0 {
+		switch {
+		case location == "-":
+			roots = []string{"."}
+		case IsURL(location):
+			roots = []string{location[:strings.LastIndex(location, "/")+1]}
+		default:
+			roots = []string{filepath.Dir(location)}
+		}
+	}
+
+	result := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if IsURL(root) {
+			result = append(result, root)
+			continue
+		}
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, abs)
+	}
+	return result, nil
+}
+
+// Reads spec or ref. Files and URLs outside of ref roots are not read
+func (r *reader) read(_ *openapi3.Loader, location *url.URL) ([]byte, error) {
+	if location.Scheme == "http" || location.Scheme == "https" {
+		if !r.isAllowed(location.String()) {
+			return nil, fmt.Errorf("%s is outside of %s", location, strings.Join(r.roots, ", "))
+		}
+		data, err := r.fetch(location)
+		if err != nil && len(r.options.FallbackDir) > 0 {
+			path := filepath.Join(r.options.FallbackDir, location.Host, filepath.FromSlash(location.Path))
+			if fallback, fallbackErr := ioutil.ReadFile(path); fallbackErr == nil {
+				log.Print("Loading " + location.String() + " from " + path + ". " + err.Error())
+				return fallback, nil
+			}
+		}
+		return data, err
+	}
+	if len(location.Scheme) > 0 || len(location.Host) > 0 {
+		return nil, fmt.Errorf("Unsupported location %s", location)
+	}
+
+	path, err := filepath.Abs(filepath.FromSlash(location.Path))
+	if err != nil {
+		return nil, err
+	}
+	if !r.isAllowed(path) {
+		return nil, fmt.Errorf("%s is outside of %s", location.Path, strings.Join(r.roots, ", "))
+	}
+	return ioutil.ReadFile(path)
+}
+
+func (r *reader) fetch(location *url.URL) ([]byte, error) {
+	response, err := r.client.Get(location.String())
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 400 {
+		return nil, fmt.Errorf("Loading %s failed with status %s", location, response.Status)
+	}
+	return ioutil.ReadAll(response.Body)
+}
+
+// Returns true if URL or absolute path is in one of ref roots
+func (r *reader) isAllowed(location string) bool {
+	if location == r.location {
+		return true
+	}
+	for _, root := range r.roots {
+		if IsURL(root) {
+			if IsURL(location) && strings.HasPrefix(location, root) {
+				return true
+			}
+			continue
+		}
+		if IsURL(location) {
+			continue
+		}
+		if rel, err := filepath.Rel(root, location); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"openapi-to-graphql/utils"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Errors of invalid spec, one per invalid part of spec, e.g. paths./pets: ...
+type ValidationError struct {
+	Location string
+	Errors   []string
+}
+
+func (e *ValidationError) Error() string {
+	return "Invalid spec " + e.Location + ":\n  - " + strings.Join(e.Errors, "\n  - ")
+}
+
+// Validates spec with kin-openapi. Every invalid component, path, info, servers and security is listed in ValidationError
+func Validate(doc *openapi3.T, location string, strictFormats bool) error {
+	if !strictFormats {
+		defer func(disabled bool) {
+			openapi3.SchemaFormatValidationDisabled = disabled
+		}(openapi3.SchemaFormatValidationDisabled)
+		openapi3.SchemaFormatValidationDisabled = true
+	}
+
+	ctx := context.Background()
+	err := doc.Validate(ctx)
+	if err == nil {
+		return nil
+	}
+
+	errs := make([]string, 0)
+	add := func(name string, err error) {
+		if err != nil {
+			errs = append(errs, name+": "+err.Error())
+		}
+	}
+
+	if len(doc.OpenAPI) == 0 {
+		add("openapi", errors.New("must be a non-empty string"))
+	}
+	if doc.Info == nil {
+		add("info", errors.New("must be an object"))
+	} else {
+		add("info", doc.Info.Validate(ctx))
+	}
+	for _, name := range sortedNames(doc.Components.Schemas) {
+		add("components.schemas."+name, doc.Components.Schemas[name].Validate(ctx))
+	}
+	for _, name := range sortedNames(doc.Components.Parameters) {
+		add("components.parameters."+name, doc.Components.Parameters[name].Validate(ctx))
+	}
+	for _, name := range sortedNames(doc.Components.RequestBodies) {
+		add("components.requestBodies."+name, doc.Components.RequestBodies[name].Validate(ctx))
+	}
+	for _, name := range sortedNames(doc.Components.Responses) {
+		add("components.responses."+name, doc.Components.Responses[name].Validate(ctx))
+	}
+	for _, name := range sortedNames(doc.Components.Headers) {
+		add("components.headers."+name, doc.Components.Headers[name].Validate(ctx))
+	}
+	for _, name := range sortedNames(doc.Components.SecuritySchemes) {
+		add("components.securitySchemes."+name, doc.Components.SecuritySchemes[name].Validate(ctx))
+	}
+	if doc.Paths == nil {
+		add("paths", errors.New("must be an object"))
+	} else {
+		for _, path := range utils.GetPaths(doc) {
+			add("paths."+path, doc.Paths[path].Validate(ctx))
+		}
+	}
+	if doc.Security != nil {
+		add("security", doc.Security.Validate(ctx))
+	}
+	if doc.Servers != nil {
+		add("servers", doc.Servers.Validate(ctx))
+	}
+
+	// error is not in validated parts, e.g. parameters of path do not match
+	if len(errs) == 0 {
+		errs = append(errs, err.Error())
+	}
+	return &ValidationError{Location: location, Errors: errs}
+}
+
+// Returns sorted names of components
+func sortedNames(components interface{}) []string {
+	names := make([]string, 0)
+	switch components := components.(type) {
+	case openapi3.Schemas:
+		for name := range components {
+			names = append(names, name)
+		}
+	case openapi3.ParametersMap:
+		for name := range components {
+			names = append(names, name)
+		}
+	case openapi3.RequestBodies:
+		for name := range components {
+			names = append(names, name)
+		}
+	case openapi3.Responses:
+		for name := range components {
+			names = append(names, name)
+		}
+	case openapi3.Headers:
+		for name := range components {
+			names = append(names, name)
+		}
+	case openapi3.SecuritySchemes:
+		for name := range components {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -1,44 +1,419 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
+	"openapi-to-graphql/breaker"
+	"openapi-to-graphql/cache"
+	"openapi-to-graphql/cassette"
+	"openapi-to-graphql/codegen"
+	"openapi-to-graphql/loader"
+	"openapi-to-graphql/metrics"
+	"openapi-to-graphql/naming"
 	"openapi-to-graphql/oas_utils"
+	"openapi-to-graphql/subscriptions"
+	"openapi-to-graphql/tracing"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/handler"
 )
 
-var oasPath = flag.String("path", "oas/1/spec.json", "Path to oas json spec")
+var oasPath = flag.String("path", "oas/1/spec.json", "Comma separated paths or URLs of oas json or yaml specs, - reads stdin. Specs are merged to one schema")
+var specsPath = flag.String("specs", "", "JSON file of merged specs with name, path, namespace, typePrefix, serverUrl and headers of every spec. Overrides --path")
+var externalRefs = flag.Bool("external-refs", true, "Allow $refs to other files and URLs in directories of --ref-roots")
+var refRoots = flag.String("ref-roots", "", "Comma separated directories and URL prefixes external $refs are read from, directory of spec by default")
+var fallbackDir = flag.String("fallback-dir", "", "Directory of local copies of specs loaded from URLs, e.g. <dir>/example.com/specs/pets.yaml. Used when URL can't be fetched")
+var serverURL = flag.String("server-url", "", "Base URL of upstream, overrides servers of specs, paths and operations")
+var serverIndex = flag.Int("server-index", 0, "Index of server of spec used as upstream")
+var serverVariables = flag.String("server-variables", "", "Comma separated values of server variables, e.g. region=eu,version=v2")
+var errorUnions = flag.Bool("error-unions", false, "Return union of success and documented error response types")
+var responseWrappers = flag.Bool("response-wrappers", false, "Wrap operation results to { data, headers, status } type")
+var pagination = flag.Bool("pagination", false, "Return Relay connections from paginated list operations")
+var webhookURL = flag.String("webhook-url", "", "Public base URL of webhook receiver, e.g. http://localhost:8080/webhooks. Enables subscriptions from operation callbacks")
+var pollingSubscriptions = flag.String("poll", "", "Comma separated query fields exposed as polling subscriptions")
+var pollInterval = flag.Duration("poll-interval", subscriptions.DefaultPollInterval, "Upstream polling interval of polling subscriptions")
+var traceExporter = flag.String("trace-exporter", "", "Exporter of OpenTelemetry spans: stdout or otlp (configured by OTEL_EXPORTER_OTLP_* variables). Empty disables tracing")
+var mockMode = flag.Bool("mock", false, "Serve generated data from response examples and schemas instead of calling upstream")
+var mockSeed = flag.Int64("mock-seed", 1, "Seed of generated data in mock mode")
+var cassettePath = flag.String("cassette", "", "Cassette file of recorded upstream interactions. Empty calls upstream directly")
+var cassetteMode = flag.String("cassette-mode", string(cassette.Replay), "Cassette mode: record calls upstream and records interactions, replay serves them without upstream")
+var cacheTTL = flag.Duration("cache-ttl", 0, "Time successful GET responses of upstream are cached in memory, 0 disables the cache")
+var cacheSize = flag.Int("cache-size", 1000, "Maximum number of cached upstream responses")
+var breakerFailures = flag.Int("breaker-failures", 0, "Consecutive failures of upstream host which open its circuit breaker, 0 disables circuit breakers")
+var breakerCooldown = flag.Duration("breaker-cooldown", 30*time.Second, "Time open circuit breaker fails requests before one request is let through")
+var federationEnabled = flag.Bool("federation", false, "Serve Apollo Federation v2 subgraph with _service and _entities fields")
+var federationKeys = flag.String("federation-keys", "", "Comma separated key fields of entity types, e.g. Pet=id,Order=id sku. Keys are also set by x-graphql-key extension")
+var includeOperations = flag.String("include", "", "Comma separated rules of translated operations, e.g. tag:pets,path:/pets/**,method:get,operationId:findPets,x-internal")
+var excludeOperations = flag.String("exclude", "x-internal", "Comma separated rules of operations which are not translated, the same syntax as --include")
+var tagNamespaces = flag.Bool("tag-namespaces", false, "Group query and mutation fields in namespace fields by first tag of operation")
+var namingStrategy = flag.String("naming", naming.CamelCase, "Naming strategy of fields, arguments and enum values: camel, snake or preserve")
+var idTypes = flag.Bool("id-type", false, "Map id-like properties and parameters, e.g. id or petId, to ID type")
+var printReport = flag.Bool("report", false, "Log translated, filtered and skipped operations")
+var fileDownloadURL = flag.String("file-download-url", "/files", "Base URL of binary response download handler. Empty disables File url field")
+var metricsOperations = flag.String("metrics-operations", "", "Comma separated operation names recorded in metrics, other operations are recorded as other. Empty records the first --metrics-max-operations names")
+var metricsMaxOperations = flag.Int("metrics-max-operations", metrics.DefaultMaxOperationNames, "Maximum number of distinct operation names recorded in metrics")
+var watchSpecs = flag.Bool("watch", false, "Reload specs when spec files change or on SIGHUP. Previous schema is served if reloaded specs fail")
+var watchInterval = flag.Duration("watch-interval", time.Second, "Interval of checking modification time of spec files in watch mode")
+var listenAddress = flag.String("listen", ":8080", "Listen address of server, e.g. :8080 or 127.0.0.1:8443")
+var tlsCert = flag.String("tls-cert", "", "TLS certificate file, serves HTTPS with --tls-key")
+var tlsKey = flag.String("tls-key", "", "TLS private key file of --tls-cert")
+var corsOrigins = flag.String("cors-origins", "", "Comma separated origins allowed to send cross-origin requests, * allows every origin. Empty disables CORS")
+var corsHeaders = flag.String("cors-headers", "Content-Type, Authorization", "Request headers allowed in cross-origin requests")
+var maxBodySize = flag.Int64("max-body-size", 1<<20, "Maximum size of request body in bytes, 0 disables the limit")
+var playground = flag.Bool("playground", true, "Serve GraphQL Playground to browsers")
+var graphiql = flag.Bool("graphiql", false, "Serve GraphiQL to browsers, takes precedence over --playground")
+var readyUpstreams = flag.Bool("ready-upstreams", false, "Probe servers of specs in /readyz, server is not ready if an upstream is unreachable or responds with 5xx")
+var readyTimeout = flag.Duration("ready-timeout", 5*time.Second, "Timeout of upstream probes of /readyz")
+var shutdownGrace = flag.Duration("shutdown-grace", 5*time.Second, "Time /readyz fails on SIGTERM before server stops accepting connections")
+var shutdownTimeout = flag.Duration("shutdown-timeout", 30*time.Second, "Time in-flight requests are drained on SIGTERM before server stops")
 
 func main() {
-	// also we have to translate openapi2 to openapi3
-	public, err := openapi3.NewLoader().LoadFromFile(*oasPath)
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		codegen.Run(os.Args[2:])
+		return
+	}
+	flag.Parse()
+
+	exporter, err := tracing.NewExporter(context.Background(), *traceExporter)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	shutdownTracing := tracing.Setup(exporter, false)
+	defer shutdownTracing(context.Background())
+
+	if len(*metricsOperations) > 0 {
+		metrics.LimitOperationNames(strings.Split(*metricsOperations, ","), *metricsMaxOperations)
+	} else {
+		metrics.LimitOperationNames(nil, *metricsMaxOperations)
+	}
+
+	if (len(*tlsCert) > 0) != (len(*tlsKey) > 0) {
+		log.Fatalln("Both --tls-cert and --tls-key are required")
+	}
+
+	specConfigs, err := readSpecConfigs()
 	if err != nil {
 		log.Fatalln(err)
 	}
+	loadOptions := loader.Options{ExternalRefs: *externalRefs, FallbackDir: *fallbackDir}
+	if len(*refRoots) > 0 {
+		loadOptions.RefRoots = strings.Split(*refRoots, ",")
+	}
+	docs, err := loadSpecs(specConfigs, loadOptions)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	paths := make([]string, 0)
+	for _, doc := range docs {
+		paths = append(paths, utils.GetPaths(doc)...)
+	}
+
+	webhooks := subscriptions.NewPubSub()
+	options := types.Options{
+		ErrorUnions:      *errorUnions,
+		ResponseWrappers: *responseWrappers,
+		Pagination:       *pagination,
+		FileDownloadURL:  *fileDownloadURL,
+		WebhookURL:       *webhookURL,
+		Webhooks:         webhooks,
+		PollInterval:     *pollInterval,
+		Mock:             *mockMode,
+		MockSeed:         *mockSeed,
+		TagNamespaces:    *tagNamespaces,
+		IDTypes:          *idTypes,
+		Federation:       *federationEnabled,
+		FederationKeys:   make(map[string]string),
+		ServerURL:        *serverURL,
+		ServerIndex:      *serverIndex,
+		ServerVariables:  make(map[string]string),
+	}
+	var recorder *cassette.Transport
+	if len(*cassettePath) > 0 {
+		recorder, err = cassette.NewTransport(cassette.Mode(*cassetteMode), *cassettePath, paths, nil)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		options.Transport = recorder
+	}
+	// cached responses are served while circuit breaker is open
+	if *breakerFailures > 0 {
+		options.Transport = breaker.NewTransport(*breakerFailures, *breakerCooldown, options.Transport)
+	}
+	if *cacheTTL > 0 {
+		options.Transport = cache.NewTransport(*cacheTTL, *cacheSize, options.Transport)
+	}
+	if len(*pollingSubscriptions) > 0 {
+		options.PollingSubscriptions = strings.Split(*pollingSubscriptions, ",")
+	}
+	for _, key := range strings.Split(*federationKeys, ",") {
+		if pair := strings.SplitN(key, "=", 2); len(pair) == 2 {
+			options.FederationKeys[strings.TrimSpace(pair[0])] = strings.TrimSpace(pair[1])
+		}
+	}
+	for _, variable := range strings.Split(*serverVariables, ",") {
+		if pair := strings.SplitN(variable, "=", 2); len(pair) == 2 {
+			options.ServerVariables[strings.TrimSpace(pair[0])] = strings.TrimSpace(pair[1])
+		}
+	}
+	if options.Include, err = parseSelector(*includeOperations); err != nil {
+		log.Fatalln(err)
+	}
+	if options.Exclude, err = parseSelector(*excludeOperations); err != nil {
+		log.Fatalln(err)
+	}
+
+	schemaHandler, err := newSchemaHandler(specConfigs, docs, options)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	current := &currentHandler{}
+	current.swap(schemaHandler)
+	mux := http.NewServeMux()
+	mux.Handle("/", current)
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", healthHandler)
+	if len(options.WebhookURL) > 0 {
+		mux.Handle(utils.GetURLPath(options.WebhookURL), subscriptions.NewWebhookHandler(webhooks, utils.GetURLPath(options.WebhookURL)))
+	}
+	if *watchSpecs {
+		if err := checkWatchable(specConfigs); err != nil {
+			log.Fatalln(err)
+		}
+		go watch(current, specConfigs, loadOptions, options, *watchInterval)
+	}
+
+	var h http.Handler = mux
+	if *maxBodySize > 0 {
+		h = withBodyLimit(h, *maxBodySize)
+	}
+	if origins := getCORSOrigins(); len(origins) > 0 {
+		h = withCORS(h, origins, *corsHeaders)
+	}
+	server := &http.Server{
+		Addr:              *listenAddress,
+		Handler:           tracing.NewHandler(h),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Print("Server is listening " + *listenAddress)
+	err = serve(server, *tlsCert, *tlsKey, *shutdownGrace, *shutdownTimeout)
+	// recorded interactions are saved when server stops
+	if recorder != nil {
+		if err := recorder.Close(); err != nil {
+			log.Print("Error when saving cassette ", err)
+		}
+	}
+	if err != nil {
+		log.Panic("Error when starting the http server", err)
+	}
+	log.Print("Server stopped")
+}
+
+// Spec of --specs file. Values of headers and server variables expand environment variables, e.g. Bearer ${PETSTORE_TOKEN}.
+// Server URL, index and variables override command line flags
+type specConfig struct {
+	Name            string            `json:"name"`
+	Path            string            `json:"path"`
+	Namespace       bool              `json:"namespace"`
+	TypePrefix      string            `json:"typePrefix"`
+	ServerURL       string            `json:"serverUrl"`
+	ServerIndex     *int              `json:"serverIndex"`
+	ServerVariables map[string]string `json:"serverVariables"`
+	Headers         map[string]string `json:"headers"`
+}
+
+func readSpecConfigs() ([]*specConfig, error) {
+	specConfigs := make([]*specConfig, 0)
+	if len(*specsPath) == 0 {
+		for i, path := range strings.Split(*oasPath, ",") {
+			specConfigs = append(specConfigs, &specConfig{Name: "spec" + strconv.Itoa(i+1), Path: path})
+		}
+		return specConfigs, nil
+	}
 
-	config := oas_utils.TranslateToSchemaConfig(public)
+	data, err := ioutil.ReadFile(*specsPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &specConfigs); err != nil {
+		return nil, err
+	}
+	for i, specConfig := range specConfigs {
+		if len(specConfig.Name) == 0 {
+			specConfig.Name = "spec" + strconv.Itoa(i+1)
+		}
+	}
+	return specConfigs, nil
+}
+
+// Returns spec with options of spec config. Files of merged specs are downloaded from own handler, e.g. /files/petstore
+func (c *specConfig) spec(oas *openapi3.T, options types.Options, merged bool) oas_utils.Spec {
+	if len(c.ServerURL) > 0 {
+		options.ServerURL = c.ServerURL
+	}
+	if loader.IsURL(c.Path) {
+		options.SpecURL = c.Path
+	}
+	if c.ServerIndex != nil {
+		options.ServerIndex = *c.ServerIndex
+	}
+	if len(c.ServerVariables) > 0 {
+		variables := make(map[string]string)
+		for name, value := range options.ServerVariables {
+			variables[name] = value
+		}
+		for name, value := range c.ServerVariables {
+			variables[name] = os.ExpandEnv(value)
+		}
+		options.ServerVariables = variables
+	}
+	options.TypePrefix = c.TypePrefix
+	if len(c.Headers) > 0 {
+		options.Headers = http.Header{}
+		for name, value := range c.Headers {
+			options.Headers.Set(name, os.ExpandEnv(value))
+		}
+	}
+	if merged && len(options.FileDownloadURL) > 0 {
+		options.FileDownloadURL = strings.TrimSuffix(options.FileDownloadURL, "/") + "/" + c.Name
+	}
+	return oas_utils.Spec{Name: c.Name, OAS: oas, Namespace: c.Namespace, Options: options}
+}
+
+// Loads specs of spec configs, in order of configs
+func loadSpecs(specConfigs []*specConfig, options loader.Options) ([]*openapi3.T, error) {
+	docs := make([]*openapi3.T, 0, len(specConfigs))
+	for _, specConfig := range specConfigs {
+		// also we have to translate openapi2 to openapi3
+		doc, err := loader.Load(specConfig.Path, options)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// Returns handler of GraphQL, subscription and file download requests of schema translated from specs.
+// Every call translates specs with new namers and report, so handlers of reloaded specs don't share state
+func newSchemaHandler(specConfigs []*specConfig, docs []*openapi3.T, options types.Options) (http.Handler, error) {
+	if *printReport {
+		options.Report = &types.Report{}
+	}
+	specs := make([]oas_utils.Spec, 0, len(specConfigs))
+	for i, specConfig := range specConfigs {
+		// names are assigned by method and path, so specs can't share namer
+		namer, err := naming.NewNamer(*namingStrategy)
+		if err != nil {
+			return nil, err
+		}
+		options.Namer = namer
+		specs = append(specs, specConfig.spec(docs[i], options, len(specConfigs) > 1))
+	}
+	config, err := oas_utils.MergeSchemaConfigs(specs)
+	if err != nil {
+		return nil, err
+	}
+	if options.Report != nil {
+		log.Print("Translation report:\n" + options.Report.String())
+	}
+	config.Extensions = append(config.Extensions, metrics.Extension{})
 
 	schema, err := graphql.NewSchema(config)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	h := handler.New(&handler.Config{
 		Schema:     &schema,
 		Pretty:     true,
-		GraphiQL:   false,
-		Playground: true,
+		GraphiQL:   *graphiql,
+		Playground: *playground,
 	})
 
-	http.Handle("/", h)
+	ws := subscriptions.NewWebsocketHandler(&schema, getCORSOrigins())
+	sse := subscriptions.NewSSEHandler(&schema)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if subscriptions.IsWebsocketRequest(r) {
+			ws.ServeHTTP(w, r)
+			return
+		}
+		if subscriptions.IsSSERequest(r) {
+			sse.ServeHTTP(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+	upstreams := make([]string, 0)
+	for _, spec := range specs {
+		if len(spec.Options.FileDownloadURL) > 0 && !spec.Options.Mock {
+			fileHandler := oas_utils.NewFileHandler(spec.OAS, spec.Options)
+			mux.Handle(oas_utils.GetFileDownloadPath(spec.Options), fileHandler)
+		}
+		if *readyUpstreams && !spec.Options.Mock {
+			upstream, err := oas_utils.GetServerUrl(spec.OAS, spec.Options)
+			if err != nil {
+				return nil, err
+			}
+			if !utils.Contains(upstreams, upstream) {
+				upstreams = append(upstreams, upstream)
+			}
+		}
+	}
+	// upstreams of reloaded specs are probed by handler of reloaded schema
+	mux.Handle("/readyz", newReadyHandler(upstreams, &http.Client{}, *readyTimeout))
+	return mux, nil
+}
 
-	log.Print("Server is listening port 8080")
-	err = http.ListenAndServe(":8080", nil)
-	if err != nil {
-		log.Panic("Error when starting the http server", err)
+// Returns origins of --cors-origins, nil if it is empty
+func getCORSOrigins() []string {
+	if len(*corsOrigins) == 0 {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(*corsOrigins, " ", ""), ",")
+}
+
+// Returns selector of rules like tag:pets,path:/pets/**,method:get,operationId:findPets,x-internal. Returns nil if value is empty
+func parseSelector(value string) (*types.OperationSelector, error) {
+	if len(strings.TrimSpace(value)) == 0 {
+		return nil, nil
+	}
+	selector := &types.OperationSelector{}
+	for _, rule := range strings.Split(value, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "x-internal" {
+			selector.Internal = true
+			continue
+		}
+		pair := strings.SplitN(rule, ":", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("Invalid operation rule %s", rule)
+		}
+		switch pair[0] {
+		case "tag":
+			selector.Tags = append(selector.Tags, pair[1])
+		case "path":
+			selector.Paths = append(selector.Paths, pair[1])
+		case "method":
+			selector.Methods = append(selector.Methods, pair[1])
+		case "operationId":
+			selector.OperationIds = append(selector.OperationIds, pair[1])
+		default:
+			return nil, fmt.Errorf("Unknown operation rule %s", rule)
+		}
 	}
+	return selector, nil
 }
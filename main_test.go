@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"openapi-to-graphql/loader"
+	"openapi-to-graphql/types"
+)
+
+const mergedSpec = `{
+	"openapi": "3.0.0",
+	"info": { "title": "%NAME%", "version": "1.0.0" },
+	"servers": [{ "url": "%SERVER%/%NAME%" }],
+	"paths": {
+		"/pets": { "get": { "operationId": "%PETS%", "responses": { "200": { "$ref": "#/components/responses/Named" } } } },
+		"%THING_PATH%": { "get": { "operationId": "getThing", "responses": { "200": { "$ref": "#/components/responses/Named" } } } }
+	},
+	"components": {
+		"responses": {
+			"Named": {
+				"description": "Named",
+				"content": {
+					"application/json": {
+						"schema": { "type": "object", "properties": { "name": { "type": "string" } } }
+					}
+				}
+			}
+		}
+	}
+}`
+
+// Operations of the same method and path or operationId in namespaced specs are named by their own spec
+func TestMergedSpecNames(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"` + r.URL.Path + `"}`))
+	}))
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	specConfigs := []*specConfig{
+		{Name: "petstore", Path: filepath.Join(dir, "petstore.json"), Namespace: true},
+		{Name: "zoo", Path: filepath.Join(dir, "zoo.json"), Namespace: true},
+	}
+	writeMergedSpec(t, specConfigs[0].Path, "petstore", upstream.URL, "findPets", "/things")
+	writeMergedSpec(t, specConfigs[1].Path, "zoo", upstream.URL, "listAnimals", "/items")
+
+	docs, err := loadSpecs(specConfigs, loader.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := newSchemaHandler(specConfigs, docs, types.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertQuery(t, h, "{ petstore { findPets { name } getThing { name } } zoo { listAnimals { name } getThing { name } } }",
+		`{"data":{"petstore":{"findPets":{"name":"/petstore/pets"},"getThing":{"name":"/petstore/things"}},"zoo":{"getThing":{"name":"/zoo/items"},"listAnimals":{"name":"/zoo/pets"}}}}`)
+}
+
+func writeMergedSpec(t *testing.T, path string, name string, server string, petsOperationId string, thingPath string) {
+	spec := strings.NewReplacer("%NAME%", name, "%SERVER%", server, "%PETS%", petsOperationId, "%THING_PATH%", thingPath).Replace(mergedSpec)
+	if err := ioutil.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Operations without name, or with name unknown because request is invalid, are recorded with this name
+const AnonymousOperation = "anonymous"
+
+type operationContextKey struct{}
+
+type operation struct {
+	name  string
+	start time.Time
+}
+
+// Schema extension which records GraphQL operations and latency of fields with resolvers
+type Extension struct{}
+
+func (e Extension) Name() string {
+	return "metrics"
+}
+
+// Name of operation is requested operation name, or it is set by the first resolved field.
+// Request is not parsed again to get the name
+func (e Extension) Init(ctx context.Context, p *graphql.Params) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, &operation{
+		name:  p.OperationName,
+		start: time.Now(),
+	})
+}
+
+func (e Extension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	return ctx, func(err error) {
+		if err != nil {
+			finishOperation(ctx, true)
+		}
+	}
+}
+
+func (e Extension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	return ctx, func(errs []gqlerrors.FormattedError) {
+		if len(errs) > 0 {
+			finishOperation(ctx, true)
+		}
+	}
+}
+
+func (e Extension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	return ctx, func(result *graphql.Result) {
+		finishOperation(ctx, result.HasErrors())
+	}
+}
+
+// Fields resolved by default resolver are not recorded
+func (e Extension) ResolveFieldDidStart(ctx context.Context, info *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	setOperationName(ctx, info)
+	if !hasResolver(info) {
+		return ctx, func(interface{}, error) {}
+	}
+	field := info.ParentType.Name() + "." + info.FieldName
+	start := time.Now()
+	return ctx, func(_ interface{}, err error) {
+		observeResolver(field, err != nil, time.Since(start))
+	}
+}
+
+func (e Extension) HasResult() bool {
+	return false
+}
+
+func (e Extension) GetResult(context.Context) interface{} {
+	return nil
+}
+
+func finishOperation(ctx context.Context, failed bool) {
+	if op, ok := ctx.Value(operationContextKey{}).(*operation); ok {
+		name := op.name
+		if len(name) == 0 {
+			name = AnonymousOperation
+		}
+		observeOperation(name, failed, time.Since(op.start))
+	}
+}
+
+// Sets name of executed operation parsed by graphql-go if it is not requested by name
+func setOperationName(ctx context.Context, info *graphql.ResolveInfo) {
+	op, ok := ctx.Value(operationContextKey{}).(*operation)
+	if !ok || len(op.name) > 0 {
+		return
+	}
+	if definition, ok := info.Operation.(*ast.OperationDefinition); ok && definition.Name != nil {
+		op.name = definition.Name.Value
+	}
+}
+
+func hasResolver(info *graphql.ResolveInfo) bool {
+	object, ok := info.ParentType.(*graphql.Object)
+	if !ok {
+		return false
+	}
+	field, ok := object.Fields()[info.FieldName]
+	return ok && field.Resolve != nil
+}
@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "openapi_to_graphql"
+
+var (
+	operationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "graphql_operations_total",
+		Help:      "GraphQL operations by operation name and status (success or error)",
+	}, []string{"operation_name", "status"})
+
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "graphql_operation_duration_seconds",
+		Help:      "Duration of GraphQL operations by operation name",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation_name"})
+
+	resolverDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "graphql_resolver_duration_seconds",
+		Help:      "Latency of GraphQL field resolvers by field, e.g. Query.findPets",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"field", "status"})
+
+	upstreamRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_total",
+		Help:      "Upstream HTTP requests by operationId, method and status code. Status code is error if request failed",
+	}, []string{"operation_id", "method", "status_code"})
+
+	upstreamRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Latency of upstream HTTP requests by operationId and method",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation_id", "method"})
+
+	cacheLookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Lookups of upstream response cache by result (hit or miss). Hit ratio is rate of hits divided by rate of all lookups",
+	}, []string{"result"})
+
+	circuitBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_state",
+		Help:      "State of circuit breaker by upstream host: 0 closed, 1 half-open, 2 open",
+	}, []string{"upstream"})
+
+	circuitBreakerRejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_rejected_requests_total",
+		Help:      "Upstream requests failed by open circuit breaker without calling upstream, by upstream host",
+	}, []string{"upstream"})
+)
+
+// Default limit of distinct operation names
+const DefaultMaxOperationNames = 100
+
+// Operation name of operations which are not allowed or exceed limit of distinct names
+const OtherOperation = "other"
+
+// Operation names are sent by clients, distinct names are limited so clients can't create unbounded series
+var operationNames = &labelValues{max: DefaultMaxOperationNames, values: make(map[string]bool)}
+
+type labelValues struct {
+	mu      sync.Mutex
+	allowed []string
+	max     int
+	values  map[string]bool
+}
+
+// Limits recorded operation names. Only allowed names are recorded if allowed is not empty,
+// otherwise the first max distinct names. Other operations are recorded as OtherOperation
+func LimitOperationNames(allowed []string, max int) {
+	operationNames.mu.Lock()
+	defer operationNames.mu.Unlock()
+	operationNames.allowed = allowed
+	operationNames.max = max
+	operationNames.values = make(map[string]bool)
+}
+
+// Returns value or OtherOperation if value is not allowed or limit of values is reached
+func (l *labelValues) get(value string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.allowed) > 0 {
+		for _, allowed := range l.allowed {
+			if allowed == value {
+				return value
+			}
+		}
+		return OtherOperation
+	}
+	if !l.values[value] {
+		if len(l.values) >= l.max {
+			return OtherOperation
+		}
+		l.values[value] = true
+	}
+	return value
+}
+
+// Returns handler of metrics in Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Records upstream request. Status code 0 means request failed without response
+func ObserveUpstreamRequest(operationId string, method string, statusCode int, duration time.Duration) {
+	status := "error"
+	if statusCode > 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	upstreamRequestsTotal.WithLabelValues(operationId, method, status).Inc()
+	upstreamRequestDuration.WithLabelValues(operationId, method).Observe(duration.Seconds())
+}
+
+// Records lookup of upstream response cache
+func ObserveCacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookupsTotal.WithLabelValues(result).Inc()
+}
+
+// Records state of circuit breaker of upstream host
+func SetCircuitState(upstream string, state int) {
+	circuitBreakerState.WithLabelValues(upstream).Set(float64(state))
+}
+
+// Records request rejected by open circuit breaker of upstream host
+func ObserveCircuitRejection(upstream string) {
+	circuitBreakerRejectedTotal.WithLabelValues(upstream).Inc()
+}
+
+func observeOperation(operationName string, failed bool, duration time.Duration) {
+	if operationName != AnonymousOperation {
+		operationName = operationNames.get(operationName)
+	}
+	operationsTotal.WithLabelValues(operationName, getStatus(failed)).Inc()
+	operationDuration.WithLabelValues(operationName).Observe(duration.Seconds())
+}
+
+func observeResolver(field string, failed bool, duration time.Duration) {
+	resolverDuration.WithLabelValues(field, getStatus(failed)).Observe(duration.Seconds())
+}
+
+func getStatus(failed bool) string {
+	if failed {
+		return "error"
+	}
+	return "success"
+}
@@ -0,0 +1,278 @@
+package mock
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/rand"
+	"openapi-to-graphql/types"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Optional properties and list items are generated up to this depth, deeper values have only required properties
+const maxDepth = 3
+
+// Self-referencing required properties stop at this depth
+const maxRequiredDepth = 8
+
+var words = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do", "eiusmod", "tempor"}
+
+var baseTime = time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Generates values which conform to data definitions. Generator with the same seed generates the same values
+type Generator struct {
+	rand *rand.Rand
+}
+
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Returns JSON compatible value of definition. Schema example is used if present
+func (g *Generator) Value(def *types.DataDefinition) interface{} {
+	return g.value(def, 0)
+}
+
+// Returns random bytes, e.g. body of binary response
+func (g *Generator) Bytes(n int) []byte {
+	b := make([]byte, n)
+	g.rand.Read(b)
+	return b
+}
+
+func (g *Generator) value(def *types.DataDefinition, depth int) interface{} {
+	if def == nil || depth > maxRequiredDepth {
+		return nil
+	}
+	if def.Schema != nil && def.Schema.Example != nil {
+		return def.Schema.Example
+	}
+
+	switch def.TargetGraphQLType {
+	case types.List:
+		return g.list(def, depth)
+	case types.Object:
+		return g.object(def, depth)
+	case types.Enum:
+		if len(def.Schema.Enum) == 0 {
+			return nil
+		}
+		return def.Schema.Enum[g.rand.Intn(len(def.Schema.Enum))]
+	case types.Union:
+		if len(def.UnionDefinitions) == 0 {
+			return nil
+		}
+		return g.value(def.UnionDefinitions[g.rand.Intn(len(def.UnionDefinitions))], depth)
+	case types.JSON:
+		return map[string]interface{}{}
+	case types.String:
+		return g.String(def.Schema)
+	case types.Integer:
+		value, ok := g.number(def.Schema, 1, 100, integerStep(def.Schema))
+		if !ok {
+			return nil
+		}
+		return int64(value)
+	case types.Float:
+		value, ok := g.number(def.Schema, 0, 100, floatStep(def.Schema))
+		// bounds closer than 2 decimals get more decimals
+		for step := 0.0001; !ok && def.Schema.MultipleOf == nil && step > 1e-12; step /= 100 {
+			value, ok = g.number(def.Schema, 0, 100, step)
+		}
+		if !ok {
+			return nil
+		}
+		return value
+	case types.Boolean:
+		return g.rand.Intn(2) == 1
+	}
+	return nil
+}
+
+func (g *Generator) list(def *types.DataDefinition, depth int) []interface{} {
+	min := int(def.Schema.MinItems)
+	max := min + 3
+	if def.Schema.MaxItems != nil && int(*def.Schema.MaxItems) < max {
+		max = int(*def.Schema.MaxItems)
+	}
+	n := min
+	if depth < maxDepth && max > min {
+		n = min + 1 + g.rand.Intn(max-min)
+	}
+
+	items := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, g.value(def.ListItemDefinitions, depth+1))
+	}
+	return items
+}
+
+func (g *Generator) object(def *types.DataDefinition, depth int) map[string]interface{} {
+	names := make([]string, 0, len(def.ObjectPropertiesDefinitions))
+	for name := range def.ObjectPropertiesDefinitions {
+		names = append(names, name)
+	}
+	// properties are generated in the same order to get the same values from the same seed
+	sort.Strings(names)
+
+	object := make(map[string]interface{})
+	for _, name := range names {
+		if depth >= maxDepth && !isRequired(def.Schema, name) {
+			continue
+		}
+		if value := g.value(def.ObjectPropertiesDefinitions[name], depth+1); value != nil {
+			object[name] = value
+		}
+	}
+	return object
+}
+
+// Returns string of schema format or pattern, otherwise words of length between schema min and max length
+func (g *Generator) String(schema *openapi3.Schema) string {
+	switch schema.Format {
+	case "date-time":
+		return g.time().Format(time.RFC3339)
+	case "date":
+		return g.time().Format("2006-01-02")
+	case "time":
+		return g.time().Format("15:04:05")
+	case "email":
+		return g.word() + fmt.Sprint(g.rand.Intn(100)) + "@example.com"
+	case "uuid":
+		b := g.Bytes(16)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "uri", "url":
+		return "https://example.com/" + g.word()
+	case "hostname":
+		return g.word() + ".example.com"
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", g.rand.Intn(256))
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", g.rand.Intn(65536))
+	case "byte":
+		return base64.StdEncoding.EncodeToString(g.Bytes(8))
+	}
+
+	if len(schema.Pattern) > 0 {
+		if s, err := g.pattern(schema.Pattern); err == nil {
+			return s
+		}
+	}
+
+	minLength := int(schema.MinLength)
+	maxLength := minLength + 20
+	if schema.MaxLength != nil {
+		maxLength = int(*schema.MaxLength)
+	}
+
+	s := g.word()
+	for len(s) < minLength {
+		s += " " + g.word()
+	}
+	if len(s) > maxLength {
+		s = strings.TrimSpace(s[:maxLength])
+	}
+	// string cut before space is padded back to min length
+	for len(s) < minLength {
+		word := g.word()
+		if len(word) > minLength-len(s) {
+			word = word[:minLength-len(s)]
+		}
+		s += word
+	}
+	return s
+}
+
+// Returns multiple of step between schema minimum and maximum, or default bounds if schema has none.
+// Returns false if there is no multiple of step between bounds
+func (g *Generator) number(schema *openapi3.Schema, defaultMin float64, defaultMax float64, step float64) (float64, bool) {
+	min, max := defaultMin, defaultMax
+	if schema.Min != nil {
+		min = *schema.Min
+		if schema.Max == nil {
+			max = min + defaultMax - defaultMin
+		}
+	}
+	if schema.Max != nil {
+		max = *schema.Max
+		if schema.Min == nil {
+			min = math.Min(defaultMin, max)
+		}
+	}
+
+	// range of multiples of step inside bounds, tolerance absorbs float error of e.g. 0.3 / 0.1
+	const tolerance = 1e-9
+	lo := math.Ceil(min/step - tolerance)
+	hi := math.Floor(max/step + tolerance)
+	if schema.ExclusiveMin && lo <= min/step+tolerance {
+		lo++
+	}
+	if schema.ExclusiveMax && hi >= max/step-tolerance {
+		hi--
+	}
+	if lo > hi {
+		return 0, false
+	}
+
+	k := lo + math.Floor(g.rand.Float64()*(hi-lo+1))
+	if k > hi {
+		k = hi
+	}
+	// rounding to decimals of step removes float error, e.g. 3 * 0.3 is 0.8999999999999999
+	for scale := 1.0; scale <= 1e15; scale *= 10 {
+		if scaled := step * scale; math.Abs(scaled-math.Round(scaled)) < tolerance {
+			return math.Round(k*step*scale) / scale, true
+		}
+	}
+	return k * step, true
+}
+
+// Returns step of integer values, the smallest integer multiple of schema multipleOf or 1
+func integerStep(schema *openapi3.Schema) float64 {
+	if schema.MultipleOf == nil || *schema.MultipleOf <= 0 {
+		return 1
+	}
+	step := *schema.MultipleOf
+	for n := 1.0; n <= 1000; n++ {
+		if multiple := step * n; math.Abs(multiple-math.Round(multiple)) < 1e-9 {
+			return math.Round(multiple)
+		}
+	}
+	return math.Ceil(step)
+}
+
+// Returns step of float values, schema multipleOf or 0.01 to get values with 2 decimals
+func floatStep(schema *openapi3.Schema) float64 {
+	if schema.MultipleOf == nil || *schema.MultipleOf <= 0 {
+		return 0.01
+	}
+	return *schema.MultipleOf
+}
+
+func (g *Generator) time() time.Time {
+	return baseTime.Add(time.Duration(g.rand.Intn(365*24*60)) * time.Minute)
+}
+
+func (g *Generator) word() string {
+	return words[g.rand.Intn(len(words))]
+}
+
+func isRequired(schema *openapi3.Schema, name string) bool {
+	for _, required := range schema.Required {
+		if required == name {
+			return true
+		}
+	}
+	for _, allOf := range schema.AllOf {
+		if allOf.Value != nil && isRequired(allOf.Value, name) {
+			return true
+		}
+	}
+	return false
+}
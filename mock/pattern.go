@@ -0,0 +1,86 @@
+package mock
+
+import (
+	"regexp/syntax"
+	"strings"
+)
+
+// Unbounded repetitions like * and + repeat up to this count
+const maxRepeat = 3
+
+// Returns string matching regular expression
+func (g *Generator) pattern(pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	g.writePattern(&b, re.Simplify())
+	return b.String(), nil
+}
+
+func (g *Generator) writePattern(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(g.charClassRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(byte('a' + g.rand.Intn(26)))
+	case syntax.OpCapture:
+		g.writePattern(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.writePattern(b, sub)
+		}
+	case syntax.OpAlternate:
+		g.writePattern(b, re.Sub[g.rand.Intn(len(re.Sub))])
+	case syntax.OpStar:
+		g.repeatPattern(b, re.Sub[0], 0, maxRepeat)
+	case syntax.OpPlus:
+		g.repeatPattern(b, re.Sub[0], 1, maxRepeat)
+	case syntax.OpQuest:
+		g.repeatPattern(b, re.Sub[0], 0, 1)
+	case syntax.OpRepeat:
+		max := re.Max
+		if max < 0 {
+			max = re.Min + maxRepeat
+		}
+		g.repeatPattern(b, re.Sub[0], re.Min, max)
+	}
+	// anchors, word boundaries and empty matches produce no characters
+}
+
+func (g *Generator) repeatPattern(b *strings.Builder, re *syntax.Regexp, min int, max int) {
+	n := min + g.rand.Intn(max-min+1)
+	for i := 0; i < n; i++ {
+		g.writePattern(b, re)
+	}
+}
+
+// Returns rune of class ranges, printable ASCII ranges are preferred
+func (g *Generator) charClassRune(ranges []rune) rune {
+	printable := make([]rune, 0, len(ranges))
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < ' ' {
+			lo = ' '
+		}
+		if hi > '~' {
+			hi = '~'
+		}
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) > 0 {
+		ranges = printable
+	}
+	if len(ranges) < 2 {
+		return 'x'
+	}
+
+	i := g.rand.Intn(len(ranges)/2) * 2
+	lo, hi := ranges[i], ranges[i+1]
+	return lo + rune(g.rand.Intn(int(hi-lo)+1))
+}
@@ -0,0 +1,262 @@
+package naming
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Built-in naming strategies of fields, arguments and enum values. Types are always PascalCase
+const (
+	// findPetById, petId, IN_PROGRESS
+	CamelCase = "camel"
+	// find_pet_by_id, pet_id, IN_PROGRESS
+	SnakeCase = "snake"
+	// original names with invalid characters replaced by _, e.g. find_pet_by_id of find-pet-by-id
+	Preserve = "preserve"
+)
+
+// Extension of operation, parameter, request body or schema overriding its name
+const NameExtension = "x-graphql-name"
+
+// Extension of enum schema with names of values in order of enum, e.g. ["IN_PROGRESS", "DONE"]
+const EnumVarNamesExtension = "x-enum-varnames"
+
+var invalidCharacters = regexp.MustCompile("[^_a-zA-Z0-9]")
+var repeatedUnderscores = regexp.MustCompile("_+")
+var reservedNames = []string{"true", "false", "null"}
+
+// Names that resolve to the same name in scope, e.g. arguments pet_id and petId of findPets
+type Collision = types.NameCollision
+
+// Namer of built-in strategy. Collisions get number suffix and are logged
+type DefaultNamer struct {
+	strategy string
+
+	mu sync.Mutex
+	// assigned names by original names by scope and original names by assigned names by scope
+	names      map[string]map[string]string
+	originals  map[string]map[string]string
+	collisions []Collision
+}
+
+func NewNamer(strategy string) (*DefaultNamer, error) {
+	switch strategy {
+	case "":
+		strategy = CamelCase
+	case CamelCase, SnakeCase, Preserve:
+	default:
+		return nil, fmt.Errorf("Unknown naming strategy %s", strategy)
+	}
+	return &DefaultNamer{
+		strategy:  strategy,
+		names:     make(map[string]map[string]string),
+		originals: make(map[string]map[string]string),
+	}, nil
+}
+
+// Returns namer of camel case strategy
+func NewDefaultNamer() *DefaultNamer {
+	namer, _ := NewNamer(CamelCase)
+	return namer
+}
+
+// Returns collisions found so far
+func (n *DefaultNamer) Collisions() []Collision {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Collision{}, n.collisions...)
+}
+
+func (n *DefaultNamer) OperationField(path string, method string, operation *openapi3.Operation) string {
+	scope := "Mutation"
+	if strings.EqualFold(method, "get") {
+		scope = "Query"
+	}
+	original := strings.ToUpper(method) + " " + path
+	var name string
+	if utils.GetExtension(operation.Extensions, NameExtension, &name) {
+		name = validOverride(name, "operation "+original)
+	} else {
+		name = operation.OperationID
+		if len(name) == 0 {
+			name = utils.InferResourceNameFromPath(path)
+		}
+		name = n.format(name)
+	}
+	return n.assign("field", scope, original, name)
+}
+
+func (n *DefaultNamer) Argument(field string, name string, extensions map[string]interface{}) string {
+	var override string
+	if utils.GetExtension(extensions, NameExtension, &override) {
+		return n.assign("argument", field, name, validOverride(override, "argument "+name+" of "+field))
+	}
+	return n.assign("argument", field, name, n.format(name))
+}
+
+func (n *DefaultNamer) Type(names types.SchemaNames, schema *openapi3.Schema) string {
+	var override string
+	if schema != nil && utils.GetExtension(schema.Extensions, NameExtension, &override) {
+		return validOverride(override, "type")
+	}
+
+	name := ""
+	if len(names.FromRef) > 0 {
+		name = names.FromRef
+	} else if len(names.FromSchema) > 0 {
+		name = names.FromSchema
+	} else if len(names.FromPath) > 0 {
+		name = names.FromPath
+	}
+	if n.strategy == Preserve {
+		return sanitize(name)
+	}
+	return utils.ToPascalCase(name)
+}
+
+func (n *DefaultNamer) InputType(typeName string) string {
+	return typeName + "Input"
+}
+
+// Properties are not formatted by strategy, invalid names are sanitized, e.g. created_at of created-at, _2fa of 2fa
+func (n *DefaultNamer) Property(typeName string, name string) string {
+	field := name
+	if !utils.IsValidName(name) {
+		field = sanitizeField(name)
+	}
+	return n.assign("field", typeName, name, field)
+}
+
+// Returns valid name of enum value of any JSON type, name from x-enum-varnames extension is preferred.
+// Values are upper case in camel and snake strategies, e.g. IN_PROGRESS of in-progress, _2XX of 2xx, NEGATIVE_1 of -1
+func (n *DefaultNamer) EnumValue(typeName string, schema *openapi3.Schema, index int) string {
+	value := schema.Enum[index]
+	// JSON value identifies original value, 1 and "1" are different values
+	original, _ := json.Marshal(value)
+
+	var varNames []string
+	if utils.GetExtension(schema.Extensions, EnumVarNamesExtension, &varNames) && index < len(varNames) && len(varNames[index]) > 0 {
+		return n.assign("enum value", typeName, string(original), sanitize(varNames[index]))
+	}
+
+	name := utils.EnumValueString(value)
+	if strings.HasPrefix(name, "-") {
+		name = "negative " + name[1:]
+	}
+	if len(name) == 0 {
+		name = "empty"
+	}
+	if n.strategy != Preserve {
+		name = strings.ToUpper(name)
+	}
+	name = sanitize(name)
+	if n.strategy != Preserve {
+		name = strings.Trim(repeatedUnderscores.ReplaceAllString(name, "_"), "_")
+		if len(name) == 0 || unicode.IsDigit(rune(name[0])) {
+			name = "_" + name
+		}
+	}
+	return n.assign("enum value", typeName, string(original), name)
+}
+
+// Returns name of field or argument by strategy
+func (n *DefaultNamer) format(name string) string {
+	switch n.strategy {
+	case SnakeCase:
+		return toSnakeCase(name)
+	case Preserve:
+		return sanitize(name)
+	}
+	return utils.ToCamelCase(name)
+}
+
+// Returns name assigned to original name in scope. Name taken by another original name gets number suffix
+func (n *DefaultNamer) assign(kind string, scope string, original string, name string) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.names[scope] == nil {
+		n.names[scope] = make(map[string]string)
+		n.originals[scope] = make(map[string]string)
+	}
+	if assigned, ok := n.names[scope][original]; ok {
+		return assigned
+	}
+
+	resolved := name
+	for i := 2; len(n.originals[scope][resolved]) > 0; i++ {
+		resolved = name + strconv.Itoa(i)
+	}
+	if resolved != name {
+		collision := Collision{Kind: kind, Scope: scope, Original: original, Name: name, Resolved: resolved}
+		n.collisions = append(n.collisions, collision)
+		log.Print("Naming collision: " + collision.String())
+	}
+
+	n.names[scope][original] = resolved
+	n.originals[scope][resolved] = original
+	return resolved
+}
+
+// Returns name of x-graphql-name extension, invalid name is sanitized like derived names and logged
+func validOverride(name string, of string) string {
+	valid := sanitizeField(name)
+	if valid != name {
+		log.Print("Invalid " + NameExtension + " " + name + " of " + of + " is renamed to " + valid)
+	}
+	return valid
+}
+
+// Returns valid name of field, names starting with __ are reserved for introspection
+func sanitizeField(name string) string {
+	field := sanitize(name)
+	if strings.HasPrefix(field, "__") {
+		field = sanitize("_" + strings.TrimLeft(field, "_"))
+	}
+	return field
+}
+
+// Returns valid GraphQL name, invalid characters are replaced by _
+func sanitize(name string) string {
+	name = invalidCharacters.ReplaceAllString(name, "_")
+	if len(name) == 0 || unicode.IsDigit(rune(name[0])) || utils.Contains(reservedNames, name) {
+		name = "_" + name
+	}
+	return name
+}
+
+// Returns lower case words joined by _, words are split on non-alphanumeric characters and case changes
+func toSnakeCase(name string) string {
+	words := make([]string, 0)
+	word := []rune{}
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = []rune{}
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			words = append(words, string(word))
+			word = []rune{}
+		}
+		word = append(word, unicode.ToLower(r))
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return sanitize(strings.Join(words, "_"))
+}
@@ -0,0 +1,119 @@
+// Code generated by openapi-to-graphql generate. DO NOT EDIT.
+
+package generated
+
+type BasicOneOfTest struct {
+	AnotherProperty *float64 `json:"anotherProperty,omitempty" xml:"anotherProperty"`
+}
+
+type CatMember struct {
+	CatBreed *string `json:"catBreed,omitempty" xml:"catBreed"`
+}
+
+type DogMember struct {
+	DogBreed *string `json:"dogBreed,omitempty" xml:"dogBreed"`
+}
+
+type NewPet struct {
+	Name string  `json:"name" xml:"name"`
+	Tag  *string `json:"tag,omitempty" xml:"tag"`
+}
+
+type Pet struct {
+	Id   *int    `json:"id,omitempty" xml:"id"`
+	Name *string `json:"name,omitempty" xml:"name"`
+	Tag  *string `json:"tag,omitempty" xml:"tag"`
+}
+
+type RussianDoll struct {
+	Name       *string      `json:"name,omitempty" xml:"name"`
+	NestedDoll *RussianDoll `json:"nestedDoll,omitempty" xml:"nestedDoll"`
+}
+
+type XmlPet struct {
+	Id   *int     `json:"id,omitempty" xml:"id,attr"`
+	Name *string  `json:"name,omitempty" xml:"name"`
+	Tags []string `json:"tags,omitempty" xml:"tags>tag"`
+}
+
+type Sort string
+
+const (
+	SortAsc  Sort = "asc"
+	SortDesc Sort = "desc"
+)
+
+type Sort2 string
+
+const (
+	Sort2Asc         Sort2 = "asc"
+	Sort2Desc        Sort2 = "desc"
+	Sort2ThirdOption Sort2 = "third_option"
+)
+
+// Binary response
+type File struct {
+	ContentType string  `json:"contentType"`
+	Size        int     `json:"size"`
+	Base64      string  `json:"base64"`
+	Url         *string `json:"url"`
+}
+
+// Arguments of addPet
+type AddPetArgs struct {
+	NewPetInput *NewPet `json:"newPetInput,omitempty"`
+}
+
+// Arguments of breeds
+type BreedsArgs struct {
+	BreedsInput interface{} `json:"breedsInput,omitempty"`
+}
+
+// Arguments of deletePet
+type DeletePetArgs struct {
+	Id int `json:"id"`
+}
+
+// Arguments of findPetById
+type FindPetByIdArgs struct {
+	Id   int    `json:"id"`
+	Sort *Sort2 `json:"sort,omitempty"`
+}
+
+// Arguments of findPetPhoto
+type FindPetPhotoArgs struct {
+	Id int `json:"id"`
+}
+
+// Arguments of findPets
+type FindPetsArgs struct {
+	Limit  *int     `json:"limit,omitempty"`
+	Offset *int     `json:"offset,omitempty"`
+	Sort   *Sort    `json:"sort,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// Arguments of findXmlPets
+type FindXmlPetsArgs struct {
+}
+
+// Arguments of nestedReferenceInParameter
+type NestedReferenceInParameterArgs struct {
+	RussianDoll *RussianDoll `json:"russianDoll,omitempty"`
+}
+
+// Arguments of noResponseSchema
+type NoResponseSchemaArgs struct {
+}
+
+// Arguments of updatePet
+type UpdatePetArgs struct {
+	Id          int     `json:"id"`
+	NewPetInput *NewPet `json:"newPetInput,omitempty"`
+	Sort        *Sort2  `json:"sort,omitempty"`
+}
+
+// Arguments of urlencoded
+type UrlencodedArgs struct {
+	PetInput *Pet `json:"petInput,omitempty"`
+}
@@ -3,10 +3,24 @@ package oas1
 import (
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
+	"openapi-to-graphql/cassette"
+	"openapi-to-graphql/codegen"
+	"openapi-to-graphql/naming"
+	"openapi-to-graphql/oas/1/generated"
 	oas_utils "openapi-to-graphql/oas_utils"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/graphql-go/graphql"
@@ -28,23 +42,517 @@ var cases = []TestCase{
 	union1,
 	union2,
 	nestedParameter,
+	deletePet,
+	findXmlPets,
+	findPetPhoto,
+}
+
+var errorUnionCases = []TestCase{
+	findPetByIdResult,
+	findPetByIdNotFound,
+}
+
+var responseWrapperCases = []TestCase{
+	findPetsResponse,
+}
+
+var federationCases = []TestCase{
+	petEntities,
+}
+
+var mergeCases = []TestCase{
+	mergedFindPetById,
+}
+
+var tagNamespaceCases = []TestCase{
+	petsNamespace,
+}
+
+var namingCases = []TestCase{
+	snakeCaseFindPetById,
+}
+
+var paginationCases = []TestCase{
+	findPetsConnection,
+	findPetsConnectionAfter,
+}
+
+// Upstream of tests is set by OAS1_UPSTREAM variable: replay (default) serves cassette.json without test server,
+// record calls test server and records cassette.json, live calls test server
+var upstreamMode = os.Getenv("OAS1_UPSTREAM")
+
+var transport http.RoundTripper
+
+func TestMain(m *testing.M) {
+	var recorder *cassette.Transport
+	if len(upstreamMode) == 0 {
+		upstreamMode = string(cassette.Replay)
+	}
+	if upstreamMode != string(cassette.Replay) {
+		go StartTestServer("localhost:3000")
+	}
+	if upstreamMode != "live" {
+		public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+		if err != nil {
+			log.Fatalln(err)
+		}
+		recorder, err = cassette.NewTransport(cassette.Mode(upstreamMode), "./cassette.json", utils.GetPaths(public), nil)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		transport = recorder
+	}
+
+	code := m.Run()
+	// recorded cassette is saved after all tests
+	if recorder != nil {
+		if err := recorder.Close(); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	os.Exit(code)
 }
 
 func TestCases(t *testing.T) {
-	go StartTestServer("localhost:3000")
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		Transport: transport,
+	})
+
+	runCases(t, config, cases)
+}
+
+func TestErrorUnions(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		ErrorUnions: true,
+		Transport:   transport,
+	})
+
+	runCases(t, config, errorUnionCases)
+}
+
+func TestResponseWrappers(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		ResponseWrappers: true,
+		Transport:        transport,
+	})
+
+	runCases(t, config, responseWrapperCases)
+}
+
+func TestPagination(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		Pagination: true,
+		Transport:  transport,
+	})
+
+	runCases(t, config, paginationCases)
+}
+
+func TestFederation(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		Federation:     true,
+		FederationKeys: map[string]string{"XmlPet": "id"},
+		Transport:      transport,
+	})
+
+	runCases(t, config, federationCases)
+
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: "{ _service { sdl } }"})
+	if len(r.Errors) > 0 {
+		t.Fatal(r.Errors)
+	}
+	sdl := r.Data.(map[string]interface{})["_service"].(map[string]interface{})["sdl"].(string)
+	for _, want := range []string{
+		`extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])`,
+		`type Pet @key(fields: "id") {`,
+		`type XmlPet @key(fields: "id", resolvable: false) {`,
+		"\"Binary response, e.g. application/octet-stream or image/png\"\ntype File {",
+		"type Query {",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("sdl does not contain %q", want)
+		}
+	}
+	if strings.Contains(sdl, "_entities") || strings.Contains(sdl, "_Any") {
+		t.Error("sdl contains federation fields")
+	}
+	if t.Failed() {
+		t.Log(sdl)
+	}
+}
 
+func TestMerge(t *testing.T) {
 	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	config := oas_utils.TranslateToSchemaConfig(public)
+	config, err := oas_utils.MergeSchemaConfigs([]oas_utils.Spec{
+		{Name: "petstore", OAS: public, Namespace: true, Options: types.Options{Transport: transport}},
+		{Name: "legacy", OAS: public, Options: types.Options{
+			ServerURL:  "http://localhost:3000",
+			Headers:    http.Header{"Authorization": []string{"Bearer token"}},
+			TypePrefix: "legacy",
+			Transport:  transport,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runCases(t, config, mergeCases)
 
+	_, err = oas_utils.MergeSchemaConfigs([]oas_utils.Spec{
+		{Name: "petstore", OAS: public},
+		{Name: "legacy", OAS: public},
+	})
+	if err == nil {
+		t.Error("conflicting fields of specs are merged")
+	}
+}
+
+func TestMergeFederation(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	config, err := oas_utils.MergeSchemaConfigs([]oas_utils.Spec{
+		{Name: "petstore", OAS: public, Namespace: true, Options: types.Options{Federation: true, Transport: transport}},
+		{Name: "legacy", OAS: public, Options: types.Options{Federation: true, TypePrefix: "legacy", Transport: transport}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
 	schema, err := graphql.NewSchema(config)
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	entity, ok := schema.Type("_Entity").(*graphql.Union)
+	if !ok {
+		t.Fatal("_Entity union expected")
+	}
+	names := make([]string, 0)
+	for _, object := range entity.Types() {
+		names = append(names, object.Name())
+	}
+	sort.Strings(names)
+	if want := []string{"LegacyPet", "Pet"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("entities of all specs expected: %v, got: %v", want, names)
+	}
+
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: "{ _service { sdl } }"})
+	if len(r.Errors) > 0 {
+		t.Fatal(r.Errors)
+	}
+	sdl := r.Data.(map[string]interface{})["_service"].(map[string]interface{})["sdl"].(string)
+	for _, want := range []string{`type Pet @key(fields: "id") {`, `type LegacyPet @key(fields: "id") {`} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("sdl does not contain %q", want)
+		}
+	}
+}
+
+func TestTagNamespaces(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		TagNamespaces: true,
+		Transport:     transport,
+	})
+
+	runCases(t, config, tagNamespaceCases)
+
+	pets := graphql.GetNullable(config.Query.Fields()["pets"].Type).(*graphql.Object)
+	if pets.Name() != "PetsQuery" || pets.PrivateDescription != "Everything about pets" {
+		t.Errorf("namespace type %s: %s", pets.Name(), pets.PrivateDescription)
+	}
+	if config.Query.Fields()["findPets"] != nil || config.Query.Fields()["noResponseSchema"] == nil {
+		t.Error("root fields are not grouped by tag")
+	}
+}
+
+func TestNaming(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	namer, err := naming.NewNamer(naming.SnakeCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		Namer:     namer,
+		Transport: transport,
+	})
+
+	runCases(t, config, namingCases)
+}
+
+func TestNamingCollisions(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromData([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Collisions", "version": "1.0.0"},
+		"servers": [{"url": "http://localhost:3000"}],
+		"paths": {
+			"/animals": {
+				"get": {
+					"operationId": "findAnimals",
+					"x-graphql-name": "animals",
+					"parameters": [
+						{"name": "pet-id", "in": "query", "schema": {"type": "string"}},
+						{"name": "petId", "in": "query", "schema": {"type": "string"}}
+					],
+					"responses": {"200": {"description": "", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+				}
+			},
+			"/owners": {
+				"get": {
+					"x-graphql-name": "pet-owners",
+					"responses": {"200": {"description": "", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Owner"}}}}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Owner": {
+					"x-graphql-name": "Pet Owner",
+					"type": "object",
+					"properties": {"name": {"type": "string"}}
+				},
+				"Pet": {
+					"x-graphql-name": "Animal",
+					"type": "object",
+					"properties": {"status": {"type": "string", "enum": ["sold", "SOLD"]}}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namer := naming.NewDefaultNamer()
+	report := &types.Report{}
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{Namer: namer, Report: report})
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	field := config.Query.Fields()["animals"]
+	if field == nil || field.Type.Name() != "Animal" {
+		t.Fatal("x-graphql-name is not used")
+	}
+	// invalid x-graphql-name is sanitized
+	owners := config.Query.Fields()["pet_owners"]
+	if owners == nil || owners.Type.Name() != "Pet_Owner" {
+		t.Error("invalid x-graphql-name is not sanitized")
+	}
+	if schema.Type("Status") == nil {
+		t.Error("enum type is not found")
+	}
+
+	collisions := make([]string, 0)
+	for _, collision := range namer.Collisions() {
+		collisions = append(collisions, collision.String())
+	}
+	sort.Strings(collisions)
+	want := []string{
+		"argument petId of animals is named petId2, petId is taken",
+		`enum value "SOLD" of Status is named SOLD2, SOLD is taken`,
+	}
+	if !reflect.DeepEqual(collisions, want) {
+		t.Errorf("collisions %v, want %v", collisions, want)
+	}
+	if !reflect.DeepEqual(report.Collisions, namer.Collisions()) {
+		t.Errorf("report collisions %v, want %v", report.Collisions, namer.Collisions())
+	}
+	if !strings.Contains(report.String(), "collision argument petId of animals is named petId2, petId is taken\n") {
+		t.Errorf("report does not list collisions:\n%s", report.String())
+	}
+}
+
+func TestFilter(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	report := &types.Report{}
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		Include: &types.OperationSelector{Tags: []string{"pets"}, Paths: []string{"/pets", "/pets/**", "/nestedReferenceInParameter"}},
+		Exclude: &types.OperationSelector{Paths: []string{"/pets/*/photo"}, Methods: []string{"delete"}, OperationIds: []string{"updatePet"}, Internal: true},
+		Report:  report,
+	})
+
+	translated := make([]string, 0)
+	for _, operation := range report.Filter(types.OperationTranslated) {
+		translated = append(translated, operation.OperationName)
+	}
+	reasons := make(map[string]string)
+	for _, operation := range report.Filter(types.OperationFiltered) {
+		reasons[operation.OperationName] = operation.Reason
+	}
+
+	if want := []string{"findPets", "addPet", "findPetById"}; !reflect.DeepEqual(translated, want) {
+		t.Errorf("translated %v, want %v", translated, want)
+	}
+	for name, want := range map[string]string{
+		"breeds":                     "not included",
+		"nestedReferenceInParameter": "excluded by x-internal",
+		"updatePet":                  "excluded by operationId updatePet",
+		"deletePet":                  "excluded by method DELETE",
+		"findPetPhoto":               "excluded by path /pets/*/photo",
+	} {
+		if reasons[name] != want {
+			t.Errorf("%s reason %q, want %q", name, reasons[name], want)
+		}
+	}
+	if config.Mutation.Fields()["deletePet"] != nil {
+		t.Error("filtered operation is translated")
+	}
+}
+
+// Generated package must be up to date with spec and resolve cases like runtime schema
+func TestGenerated(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	files, err := codegen.Generate(public, "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		committed, err := ioutil.ReadFile(filepath.Join("generated", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, committed) {
+			t.Errorf("generated/%s is outdated, run: go run . generate --path oas/1/spec.json --out oas/1/generated", name)
+		}
+	}
+
+	// cassette is replayed from the start, live upstream has state of other tests
+	if upstreamMode != string(cassette.Replay) {
+		t.Skip("generated cases run only with replayed upstream")
+	}
+	generatedTransport, err := cassette.NewTransport(cassette.Replay, "./cassette.json", utils.GetPaths(public), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := generated.NewClient("", &http.Client{Transport: generatedTransport})
+	schema, err := generated.NewSchema(generated.NewClientResolvers(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runSchemaCases(t, schema, cases)
+}
+
+// Upstreams with the same paths, e.g. of merged specs, replay their own interactions. Cassette is saved on close
+func TestCassetteServers(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host + r.URL.Path))
+	})
+	petstore := httptest.NewServer(handler)
+	defer petstore.Close()
+	zoo := httptest.NewServer(handler)
+	defer zoo.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cassette.json")
+	recorder, err := cassette.NewTransport(cassette.Record, path, []string{"/pets/{id}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, url := range []string{petstore.URL + "/v1/pets/1", zoo.URL + "/v1/pets/1", zoo.URL + "/v2/pets/1"} {
+		getBody(t, recorder, url)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("got cassette %v before close", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Errorf("got %d files, want only cassette", len(files))
+	}
+
+	replay, err := cassette.NewTransport(cassette.Replay, path, []string{"/pets/{id}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for url, want := range map[string]string{
+		zoo.URL + "/v1/pets/2":      strings.TrimPrefix(zoo.URL, "http://") + "/v1/pets/1",
+		zoo.URL + "/v2/pets/2":      strings.TrimPrefix(zoo.URL, "http://") + "/v2/pets/1",
+		petstore.URL + "/v1/pets/2": strings.TrimPrefix(petstore.URL, "http://") + "/v1/pets/1",
+	} {
+		if got := getBody(t, replay, url); got != want {
+			t.Errorf("got %s of %s, want %s", got, url, want)
+		}
+	}
+}
+
+func getBody(t *testing.T, transport http.RoundTripper, url string) string {
+	response, err := (&http.Client{Transport: transport}).Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func runCases(t *testing.T, config graphql.SchemaConfig, cases []TestCase) {
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runSchemaCases(t, schema, cases)
+}
+
+func runSchemaCases(t *testing.T, schema graphql.Schema, cases []TestCase) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			params := graphql.Params{Schema: schema, RequestString: tc.query}
@@ -189,3 +697,165 @@ var nestedParameter = TestCase{
 	}`,
 	expectedJson: `{"data":{"nestedReferenceInParameter":"name,name1,name2"}}`,
 }
+var deletePet = TestCase{
+	name: "deletePet",
+	query: `mutation {
+		deletePet(id: 5)
+	}`,
+	expectedJson: `{"data":{"deletePet":true}}`,
+}
+var findXmlPets = TestCase{
+	name: "findXmlPets",
+	query: `{
+		findXmlPets {
+			id
+			name
+			tags
+		}
+	}`,
+	expectedJson: `{"data":{"findXmlPets":[{"id":1,"name":"cat","tags":["cute"]},{"id":2,"name":"dog","tags":["gentle"]}]}}`,
+}
+var findPetPhoto = TestCase{
+	name: "findPetPhoto",
+	query: `{
+		findPetPhoto(id: 1) {
+			contentType
+			size
+			base64
+		}
+	}`,
+	expectedJson: `{"data":{"findPetPhoto":{"base64":"iVBORwD/","contentType":"image/png","size":6}}}`,
+}
+var findPetByIdResult = TestCase{
+	name: "findPetById result union",
+	query: `{
+		findPetById(id: 1) {
+			__typename
+			... on Pet {
+				id
+			}
+			... on NotFoundError {
+				message
+			}
+		}
+	}`,
+	expectedJson: `{"data":{"findPetById":{"__typename":"Pet","id":1}}}`,
+}
+var findPetByIdNotFound = TestCase{
+	name: "findPetById not found result union",
+	query: `{
+		findPetById(id: 100) {
+			__typename
+			... on Pet {
+				id
+			}
+			... on NotFoundError {
+				message
+			}
+		}
+	}`,
+	expectedJson: `{"data":{"findPetById":{"__typename":"NotFoundError","message":"Pet not found"}}}`,
+}
+var findPetsResponse = TestCase{
+	name: "findPets response wrapper",
+	query: `{
+		findPets(limit: 1) {
+			data {
+				id
+			}
+			headers {
+				xTotalCount
+			}
+			status
+		}
+	}`,
+	expectedJson: `{"data":{"findPets":{"data":[{"id":1}],"headers":{"xTotalCount":4},"status":200}}}`,
+}
+var findPetsConnection = TestCase{
+	name: "findPets connection",
+	query: `{
+		findPets(first: 2) {
+			edges {
+				cursor
+				node {
+					id
+				}
+			}
+			pageInfo {
+				hasNextPage
+				endCursor
+			}
+			totalCount
+		}
+	}`,
+	expectedJson: `{"data":{"findPets":{"edges":[{"cursor":"b2Zmc2V0OjE=","node":{"id":1}},{"cursor":"b2Zmc2V0OjI=","node":{"id":2}}],"pageInfo":{"endCursor":"b2Zmc2V0OjI=","hasNextPage":true},"totalCount":4}}}`,
+}
+var findPetsConnectionAfter = TestCase{
+	name: "findPets connection after cursor",
+	query: `{
+		findPets(first: 2, after: "b2Zmc2V0OjI=") {
+			edges {
+				node {
+					id
+				}
+			}
+			pageInfo {
+				hasNextPage
+				hasPreviousPage
+			}
+		}
+	}`,
+	expectedJson: `{"data":{"findPets":{"edges":[{"node":{"id":3}},{"node":{"id":4}}],"pageInfo":{"hasNextPage":false,"hasPreviousPage":true}}}}`,
+}
+
+var petEntities = TestCase{
+	name: "_entities",
+	query: `{
+		_entities(representations: [{ __typename: "Pet", id: 1 }]) {
+			... on Pet {
+				id
+				name
+			}
+		}
+	}`,
+	expectedJson: `{"data":{"_entities":[{"id":1,"name":"cat"}]}}`,
+}
+
+var mergedFindPetById = TestCase{
+	name: "merged findPetById",
+	query: `{
+		petstore {
+			findPetById(id: 1) {
+				__typename
+				name
+			}
+		}
+		legacyFindPetById(id: 1) {
+			__typename
+			name
+		}
+	}`,
+	expectedJson: `{"data":{"legacyFindPetById":{"__typename":"LegacyPet","name":"cat"},"petstore":{"findPetById":{"__typename":"Pet","name":"cat"}}}}`,
+}
+
+var petsNamespace = TestCase{
+	name: "pets namespace",
+	query: `{
+		pets {
+			findPetById(id: 1) {
+				name
+			}
+		}
+	}`,
+	expectedJson: `{"data":{"pets":{"findPetById":{"name":"cat"}}}}`,
+}
+
+var snakeCaseFindPetById = TestCase{
+	name: "snake case findPetById",
+	query: `{
+		find_pet_by_id(id: 1) {
+			name
+		}
+	}`,
+	expectedJson: `{"data":{"find_pet_by_id":{"name":"cat"}}}`,
+}
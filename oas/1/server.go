@@ -2,6 +2,7 @@ package oas1
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"net/http"
 	"sort"
@@ -59,7 +60,10 @@ func StartTestServer(addr string) {
 	router.POST("/pets", addPetHandler)
 	router.GET("/pets/:id", getPetByIdHandler)
 	router.PUT("/pets/:id", updatePetByIdHandler)
+	router.DELETE("/pets/:id", deletePetByIdHandler)
+	router.GET("/pets/:id/photo", getPetPhotoHandler)
 	router.GET("nestedReferenceInParameter", nestedReferenceInParameterHandler)
+	router.GET("/xml/pets", getXmlPetsHandler)
 	router.Run(addr)
 }
 
@@ -74,6 +78,23 @@ func nestedReferenceInParameterHandler(c *gin.Context) {
 	c.String(http.StatusOK, strings.Join(names, ","))
 }
 
+func getXmlPetsHandler(c *gin.Context) {
+	type xmlPet struct {
+		Id   int      `xml:"id,attr"`
+		Name string   `xml:"name"`
+		Tags []string `xml:"tags>tag"`
+	}
+	data := struct {
+		XMLName xml.Name `xml:"pets"`
+		Pets    []xmlPet `xml:"pet"`
+	}{}
+	for _, pet := range pets[:2] {
+		data.Pets = append(data.Pets, xmlPet{Id: pet.Id, Name: pet.Name, Tags: []string{pet.Tag}})
+	}
+
+	c.XML(http.StatusOK, data)
+}
+
 func getBreedsHandler(c *gin.Context) {
 	var body struct {
 		CatBreed bool `json:"catBreed"`
@@ -183,6 +204,32 @@ func updatePetByIdHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, found)
 }
 
+func deletePetByIdHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	for i, pet := range pets {
+		if pet.Id == id {
+			pets = append(pets[:i], pets[i+1:]...)
+			c.Status(http.StatusNoContent)
+			return
+		}
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{
+		"message": "Pet not found",
+	})
+}
+
+func getPetPhotoHandler(c *gin.Context) {
+	c.Data(http.StatusOK, "image/png", []byte{0x89, 'P', 'N', 'G', 0x00, 0xff})
+}
+
 func getPetByIdHandler(c *gin.Context) {
 	idStr, ok := c.Params.Get("id")
 	if !ok {
@@ -207,7 +254,9 @@ func getPetByIdHandler(c *gin.Context) {
 		}
 	}
 	if found == (Pet{}) {
-		c.JSON(http.StatusBadRequest, "Pet not found")
+		c.JSON(http.StatusNotFound, gin.H{
+			"message": "Pet not found",
+		})
 		return
 	}
 
@@ -247,12 +296,18 @@ func getPetsHandler(c *gin.Context) {
 	} else {
 		filtered = pets
 	}
+	c.Header("X-Total-Count", strconv.Itoa(len(filtered)))
+	if offset, err := strconv.Atoi(queryParams.Get("offset")); err == nil && offset < len(filtered) {
+		filtered = filtered[offset:]
+	}
 	if len(limit) > 0 {
 		l, err := strconv.Atoi(limit)
 		if err != nil {
 			l = 0
 		}
-		filtered = filtered[0:l]
+		if l < len(filtered) {
+			filtered = filtered[0:l]
+		}
 	}
 
 	data, _ := json.Marshal(filtered)
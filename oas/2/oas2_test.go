@@ -0,0 +1,485 @@
+package oas2
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"openapi-to-graphql/breaker"
+	"openapi-to-graphql/cache"
+	"openapi-to-graphql/metrics"
+	"openapi-to-graphql/naming"
+	oas_utils "openapi-to-graphql/oas_utils"
+	"openapi-to-graphql/subscriptions"
+	"openapi-to-graphql/tracing"
+	"openapi-to-graphql/types"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/handler"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type message struct {
+	Id      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func TestCallbackSubscription(t *testing.T) {
+	upstream := httptest.NewServer(NewTestServer())
+	defer upstream.Close()
+
+	mux := http.NewServeMux()
+	gateway := httptest.NewServer(mux)
+	defer gateway.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	public.Servers[0].URL = upstream.URL
+
+	webhooks := subscriptions.NewPubSub()
+	options := types.Options{
+		WebhookURL: gateway.URL + "/webhooks",
+		Webhooks:   webhooks,
+	}
+	schema, err := graphql.NewSchema(oas_utils.TranslateToSchemaConfigWithOptions(public, options))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Handle("/webhooks/", subscriptions.NewWebhookHandler(webhooks, "/webhooks/"))
+	mux.Handle("/graphql", subscriptions.NewWebsocketHandler(&schema, nil))
+
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(gateway.URL, "http")+"/graphql", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	conn.WriteJSON(message{Type: "connection_init"})
+	var ack message
+	if err := conn.ReadJSON(&ack); err != nil || ack.Type != "connection_ack" {
+		t.Fatalf("connection_ack expected, got: %v %v", ack, err)
+	}
+
+	payload, _ := json.Marshal(subscriptions.Request{
+		Query: `subscription {
+			subscribeOnPetEvent(subscriptionRequestInput: { kind: "adopted" }) {
+				kind
+				petName
+			}
+		}`,
+	})
+	conn.WriteJSON(message{Id: "1", Type: "subscribe", Payload: payload})
+
+	expected := []string{
+		`{"data":{"subscribeOnPetEvent":{"kind":"adopted","petName":"cat"}}}`,
+		`{"data":{"subscribeOnPetEvent":{"kind":"adopted","petName":"dog"}}}`,
+	}
+	for _, want := range expected {
+		var msg message
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Type != "next" || msg.Id != "1" {
+			t.Fatalf("next message expected, got: %s %s", msg.Type, msg.Payload)
+		}
+		got := new(bytes.Buffer)
+		json.Compact(got, msg.Payload)
+		if got.String() != want {
+			t.Logf("got:  %s", got)
+			t.Logf("want: %s", want)
+			t.Fail()
+		}
+	}
+}
+
+func TestPollingSubscription(t *testing.T) {
+	upstream := httptest.NewServer(NewTestServer())
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	public.Servers[0].URL = upstream.URL
+
+	options := types.Options{
+		PollingSubscriptions: []string{"findSubscriptions"},
+		PollInterval:         20 * time.Millisecond,
+	}
+	schema, err := graphql.NewSchema(oas_utils.TranslateToSchemaConfigWithOptions(public, options))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway := httptest.NewServer(subscriptions.NewSSEHandler(&schema))
+	defer gateway.Close()
+
+	body, _ := json.Marshal(subscriptions.Request{Query: `subscription { findSubscriptions { id } }`})
+	request, _ := http.NewRequest(http.MethodPost, gateway.URL, bytes.NewBuffer(body))
+	request.Header.Set("Accept", "text/event-stream")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	events := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			if data := strings.TrimPrefix(scanner.Text(), "data: "); data != scanner.Text() {
+				events <- data
+			}
+		}
+		close(events)
+	}()
+
+	expected := []string{
+		`{"data":{"findSubscriptions":[]}}`,
+		`{"data":{"findSubscriptions":[{"id":1}]}}`,
+	}
+	for i, want := range expected {
+		if i > 0 {
+			// unchanged polls are not sent, next event is sent after upstream change
+			subscription, _ := json.Marshal(SubscriptionRequest{Kind: "adopted", CallbackUrl: upstream.URL + "/callbacks"})
+			response, err := http.Post(upstream.URL+"/subscriptions", "application/json", bytes.NewBuffer(subscription))
+			if err != nil {
+				t.Fatal(err)
+			}
+			response.Body.Close()
+		}
+
+		select {
+		case got := <-events:
+			if got != want {
+				t.Logf("got:  %s", got)
+				t.Logf("want: %s", want)
+				t.Fail()
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("event expected: " + want)
+		}
+	}
+}
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown := tracing.Setup(exporter, true)
+	defer func() {
+		shutdown(context.Background())
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	}()
+
+	var traceparent string
+	testServer := NewTestServer()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		testServer.ServeHTTP(w, r)
+	}))
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	public.Servers[0].URL = upstream.URL
+
+	// operationId attribute doesn't depend on field name
+	namer, err := naming.NewNamer(naming.SnakeCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := graphql.NewSchema(oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{Namer: namer}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway := httptest.NewServer(tracing.NewHandler(handler.New(&handler.Config{Schema: &schema})))
+	defer gateway.Close()
+
+	body, _ := json.Marshal(subscriptions.Request{Query: `{ find_subscriptions { id } }`})
+	response, err := http.Post(gateway.URL+"/graphql", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("3 spans expected, got: %d", len(spans))
+	}
+	// spans are exported when they end, the innermost first
+	client, resolver, server := spans[0], spans[1], spans[2]
+
+	if server.Name != "POST /graphql" || resolver.Name != "resolve find_subscriptions" || client.Name != "GET /subscriptions" {
+		t.Fatalf("unexpected spans: %s, %s, %s", server.Name, resolver.Name, client.Name)
+	}
+	if resolver.Parent.SpanID() != server.SpanContext.SpanID() || client.Parent.SpanID() != resolver.SpanContext.SpanID() {
+		t.Error("resolver span should be child of server span, client span should be child of resolver span")
+	}
+
+	want := "00-" + client.SpanContext.TraceID().String() + "-" + client.SpanContext.SpanID().String() + "-01"
+	if traceparent != want {
+		t.Errorf("traceparent %s expected, got: %s", want, traceparent)
+	}
+
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, kv := range client.Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+	expected := map[attribute.Key]string{
+		tracing.OperationIdKey: "findSubscriptions",
+		"http.method":          "GET",
+		tracing.URLTemplateKey: upstream.URL + "/subscriptions",
+		"http.status_code":     "200",
+		"http.response_size":   "2",
+	}
+	for key, want := range expected {
+		if got := attributes[key].Emit(); got != want {
+			t.Errorf("%s attribute %s expected, got: %s", key, want, got)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	upstream := httptest.NewServer(NewTestServer())
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	public.Servers[0].URL = upstream.URL
+
+	config := oas_utils.TranslateToSchemaConfig(public)
+	config.Extensions = []graphql.Extension{metrics.Extension{}}
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `query MetricsTest { findSubscriptions { id } }`,
+		Context:       context.Background(),
+	})
+	if result.HasErrors() {
+		t.Fatal(result.Errors)
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	exposition := recorder.Body.String()
+
+	expected := []string{
+		`openapi_to_graphql_graphql_operations_total{operation_name="MetricsTest",status="success"} 1`,
+		`openapi_to_graphql_graphql_operation_duration_seconds_count{operation_name="MetricsTest"} 1`,
+		`openapi_to_graphql_graphql_resolver_duration_seconds_count{field="Query.findSubscriptions",status="success"}`,
+		`openapi_to_graphql_upstream_requests_total{method="GET",operation_id="findSubscriptions",status_code="200"}`,
+		`openapi_to_graphql_upstream_request_duration_seconds_count{method="GET",operation_id="findSubscriptions"}`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(exposition, want) {
+			t.Errorf("metric %s expected", want)
+		}
+	}
+	// Subscription is reserved name of root type
+	if schema.Type("Subscription2") == nil {
+		t.Fatal("Subscription2 type expected")
+	}
+	if strings.Contains(exposition, `field="Subscription2.id"`) {
+		t.Error("fields resolved by default resolver should not be recorded")
+	}
+}
+
+func TestMetricsOperationNames(t *testing.T) {
+	upstream := httptest.NewServer(NewTestServer())
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	public.Servers[0].URL = upstream.URL
+
+	config := oas_utils.TranslateToSchemaConfig(public)
+	config.Extensions = []graphql.Extension{metrics.Extension{}}
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metrics.LimitOperationNames([]string{"AllowedOperation"}, metrics.DefaultMaxOperationNames)
+	defer metrics.LimitOperationNames(nil, metrics.DefaultMaxOperationNames)
+	for _, request := range []graphql.Params{
+		{RequestString: `query AllowedOperation { findSubscriptions { id } }`},
+		{RequestString: `query RandomOperation1 { findSubscriptions { id } }`},
+		{RequestString: `query RandomOperation2 { findSubscriptions { id } } query Unused { findSubscriptions { id } }`, OperationName: "RandomOperation2"},
+	} {
+		request.Schema = schema
+		request.Context = context.Background()
+		if result := graphql.Do(request); result.HasErrors() {
+			t.Fatal(result.Errors)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	exposition := recorder.Body.String()
+
+	for _, want := range []string{
+		`openapi_to_graphql_graphql_operations_total{operation_name="AllowedOperation",status="success"} 1`,
+		`openapi_to_graphql_graphql_operations_total{operation_name="other",status="success"} 2`,
+	} {
+		if !strings.Contains(exposition, want) {
+			t.Errorf("metric %s expected", want)
+		}
+	}
+	if strings.Contains(exposition, "RandomOperation") {
+		t.Error("operation names which are not allowed should not be recorded")
+	}
+}
+
+func TestCache(t *testing.T) {
+	var calls int32
+	testServer := NewTestServer()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		testServer.ServeHTTP(w, r)
+	}))
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	public.Servers[0].URL = upstream.URL
+
+	// operationId label doesn't depend on field name
+	namer, err := naming.NewNamer(naming.SnakeCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := graphql.NewSchema(oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		Namer:     namer,
+		Transport: cache.NewTransport(time.Minute, 10, nil),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if result := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ find_subscriptions { id } }`}); result.HasErrors() {
+			t.Fatal(result.Errors)
+		}
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("1 upstream call expected, got: %d", calls)
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	exposition := recorder.Body.String()
+	for _, want := range []string{
+		`openapi_to_graphql_cache_lookups_total{result="hit"} 1`,
+		`openapi_to_graphql_cache_lookups_total{result="miss"} 1`,
+	} {
+		if !strings.Contains(exposition, want) {
+			t.Errorf("metric %s expected", want)
+		}
+	}
+	if strings.Contains(exposition, `operation_id="find_subscriptions"`) {
+		t.Error("field name should not be recorded as operationId")
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var calls int32
+	var healthy int32
+	testServer := NewTestServer()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		testServer.ServeHTTP(w, r)
+	}))
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	public.Servers[0].URL = upstream.URL
+	schema, err := graphql.NewSchema(oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		Transport: breaker.NewTransport(2, 100*time.Millisecond, nil),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := func() *graphql.Result {
+		return graphql.Do(graphql.Params{Schema: schema, RequestString: `{ findSubscriptions { id } }`})
+	}
+	host := strings.TrimPrefix(upstream.URL, "http://")
+	state := func() string {
+		recorder := httptest.NewRecorder()
+		metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		prefix := `openapi_to_graphql_circuit_breaker_state{upstream="` + host + `"} `
+		for _, line := range strings.Split(recorder.Body.String(), "\n") {
+			if strings.HasPrefix(line, prefix) {
+				return strings.TrimPrefix(line, prefix)
+			}
+		}
+		return ""
+	}
+
+	// the second consecutive failure opens circuit
+	query()
+	if state() != "0" {
+		t.Errorf("closed circuit expected after one failure, got state: %s", state())
+	}
+	query()
+	if state() != "2" {
+		t.Errorf("open circuit expected, got state: %s", state())
+	}
+	result := query()
+	if !result.HasErrors() || !strings.Contains(result.Errors[0].Message, "Circuit breaker of upstream "+host+" is open") {
+		t.Errorf("open circuit error expected, got: %v", result.Errors)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("open circuit should not call upstream, got %d calls", calls)
+	}
+
+	// request after cooldown closes circuit
+	time.Sleep(150 * time.Millisecond)
+	atomic.StoreInt32(&healthy, 1)
+	if result := query(); result.HasErrors() {
+		t.Fatal(result.Errors)
+	}
+	if state() != "0" {
+		t.Errorf("closed circuit expected after successful request, got state: %s", state())
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if want := `openapi_to_graphql_circuit_breaker_rejected_requests_total{upstream="` + host + `"} 1`; !strings.Contains(recorder.Body.String(), want) {
+		t.Errorf("metric %s expected", want)
+	}
+}
@@ -0,0 +1,84 @@
+package oas2
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionRequest struct {
+	Kind        string `json:"kind"`
+	CallbackUrl string `json:"callbackUrl"`
+}
+
+type PetEvent struct {
+	Kind    string `json:"kind"`
+	PetName string `json:"petName"`
+}
+
+type Subscription struct {
+	Id int `json:"id"`
+}
+
+var petNames = []string{"cat", "dog"}
+
+type testServer struct {
+	mu            sync.Mutex
+	subscriptions []Subscription
+}
+
+func NewTestServer() http.Handler {
+	router := gin.New()
+	server := &testServer{subscriptions: []Subscription{}}
+
+	router.GET("/subscriptions", server.findSubscriptionsHandler)
+	router.POST("/subscriptions", server.subscribeHandler)
+	return router
+}
+
+// Returns subscriptions with ETag of subscriptions count
+func (s *testServer) findSubscriptionsHandler(c *gin.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	etag := `"` + strconv.Itoa(len(s.subscriptions)) + `"`
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Header("ETag", etag)
+	c.JSON(http.StatusOK, s.subscriptions)
+}
+
+// Calls back subscriber with event of every pet
+func (s *testServer) subscribeHandler(c *gin.Context) {
+	var body SubscriptionRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&body)
+	if err != nil || len(body.CallbackUrl) == 0 {
+		c.JSON(http.StatusBadRequest, "Can't decode body")
+		return
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		for _, name := range petNames {
+			event, _ := json.Marshal(PetEvent{Kind: body.Kind, PetName: name})
+			response, err := http.Post(body.CallbackUrl, "application/json", bytes.NewBuffer(event))
+			if err == nil {
+				response.Body.Close()
+			}
+		}
+	}()
+
+	s.mu.Lock()
+	subscription := Subscription{Id: len(s.subscriptions) + 1}
+	s.subscriptions = append(s.subscriptions, subscription)
+	s.mu.Unlock()
+
+	c.JSON(http.StatusCreated, subscription)
+}
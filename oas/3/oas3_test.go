@@ -0,0 +1,202 @@
+package oas3
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"openapi-to-graphql/mock"
+	oas_utils "openapi-to-graphql/oas_utils"
+	"openapi-to-graphql/types"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/graphql-go/graphql"
+)
+
+type User struct {
+	Id        string `json:"id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	Age       int    `json:"age"`
+	Code      string `json:"code"`
+	CreatedAt string `json:"createdAt"`
+	Nickname  string `json:"nickname"`
+}
+
+const usersQuery = `{ findUsers { id email role age code createdAt nickname } }`
+
+func TestMockExample(t *testing.T) {
+	schema := newMockSchema(t, 1)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ findUserById(id: "1") { id email role age code createdAt } }`,
+	})
+	if result.HasErrors() {
+		t.Fatal(result.Errors)
+	}
+
+	got, _ := json.Marshal(result)
+	want := `{"data":{"findUserById":{"age":42,"code":"ADM-0001","createdAt":"2021-06-01T10:00:00Z","email":"admin@example.com","id":"3fa85f64-5717-4562-b3fc-2c963f66afa6","role":"ADMIN"}}}`
+	if string(got) != want {
+		t.Logf("got:  %s", got)
+		t.Logf("want: %s", want)
+		t.Fail()
+	}
+}
+
+func TestMockSchemaConformance(t *testing.T) {
+	users := findUsers(t, newMockSchema(t, 1))
+
+	if len(users) < 2 || len(users) > 5 {
+		t.Errorf("2 to 5 users expected, got: %d", len(users))
+	}
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	email := regexp.MustCompile(`^[a-z]+[0-9]*@example\.com$`)
+	code := regexp.MustCompile(`^[A-Z]{3}-[0-9]{4}$`)
+	roles := map[string]bool{"ADMIN": true, "MEMBER": true, "GUEST": true}
+
+	for _, user := range users {
+		if !uuid.MatchString(user.Id) {
+			t.Errorf("uuid expected, got: %s", user.Id)
+		}
+		if !email.MatchString(user.Email) {
+			t.Errorf("email expected, got: %s", user.Email)
+		}
+		if !roles[user.Role] {
+			t.Errorf("role enum value expected, got: %s", user.Role)
+		}
+		if user.Age < 18 || user.Age > 99 {
+			t.Errorf("age between 18 and 99 expected, got: %d", user.Age)
+		}
+		if !code.MatchString(user.Code) {
+			t.Errorf("code matching pattern expected, got: %s", user.Code)
+		}
+		if _, err := time.Parse(time.RFC3339, user.CreatedAt); err != nil {
+			t.Errorf("date-time expected, got: %s", user.CreatedAt)
+		}
+		if user.Nickname != "neo" {
+			t.Errorf("schema example expected, got: %s", user.Nickname)
+		}
+	}
+}
+
+func TestMockSeed(t *testing.T) {
+	first, _ := json.Marshal(findUsers(t, newMockSchema(t, 7)))
+	second, _ := json.Marshal(findUsers(t, newMockSchema(t, 7)))
+	if string(first) != string(second) {
+		t.Errorf("the same seed should generate the same data, got:\n%s\n%s", first, second)
+	}
+
+	other, _ := json.Marshal(findUsers(t, newMockSchema(t, 8)))
+	if string(first) == string(other) {
+		t.Error("another seed should generate another data")
+	}
+}
+
+func TestMockNumberBounds(t *testing.T) {
+	float := func(f float64) *float64 { return &f }
+	cases := []struct {
+		name       string
+		targetType int
+		schema     *openapi3.Schema
+		valid      func(value float64) bool
+	}{
+		{"exclusive minimum of integer", types.Integer, &openapi3.Schema{Min: float(0), Max: float(1), ExclusiveMin: true},
+			func(v float64) bool { return v == 1 }},
+		{"exclusive maximum of integer", types.Integer, &openapi3.Schema{Min: float(-1), Max: float(0), ExclusiveMax: true},
+			func(v float64) bool { return v == -1 }},
+		{"multipleOf of integer within maximum", types.Integer, &openapi3.Schema{Min: float(0), Max: float(10), MultipleOf: float(3)},
+			func(v float64) bool { return v >= 0 && v <= 9 && math.Mod(v, 3) == 0 }},
+		{"fractional multipleOf of integer", types.Integer, &openapi3.Schema{Min: float(1), Max: float(3), MultipleOf: float(0.5)},
+			func(v float64) bool { return v >= 1 && v <= 3 && v == math.Trunc(v) }},
+		{"exclusive minimum of float", types.Float, &openapi3.Schema{Min: float(0), Max: float(0.01), ExclusiveMin: true},
+			func(v float64) bool { return v > 0 && v <= 0.01 }},
+		{"multipleOf of float within bounds", types.Float, &openapi3.Schema{Min: float(0.5), Max: float(1.4), MultipleOf: float(0.3), ExclusiveMax: true},
+			func(v float64) bool { return v == 0.6 || v == 0.9 || v == 1.2 }},
+		{"small multipleOf of float", types.Float, &openapi3.Schema{Min: float(0), Max: float(0.01), MultipleOf: float(0.001)},
+			func(v float64) bool { return v >= 0 && v <= 0.01 && v == math.Round(v*1000)/1000 }},
+		{"bounds closer than 2 decimals", types.Float, &openapi3.Schema{Min: float(0.001), Max: float(0.002)},
+			func(v float64) bool { return v >= 0.001 && v <= 0.002 }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 100; seed++ {
+				value := mock.NewGenerator(seed).Value(&types.DataDefinition{TargetGraphQLType: c.targetType, Schema: c.schema})
+				var got float64
+				switch value := value.(type) {
+				case int64:
+					got = float64(value)
+				case float64:
+					got = value
+				default:
+					t.Fatalf("number expected, got: %v", value)
+				}
+				if !c.valid(got) {
+					t.Fatalf("value of seed %d violates schema: %v", seed, got)
+				}
+			}
+		})
+	}
+
+	value := mock.NewGenerator(1).Value(&types.DataDefinition{
+		TargetGraphQLType: types.Integer,
+		Schema:            &openapi3.Schema{Min: float(1), Max: float(2), MultipleOf: float(3)},
+	})
+	if value != nil {
+		t.Errorf("no value expected if there is no multiple between bounds, got: %v", value)
+	}
+}
+
+func TestMockStringLength(t *testing.T) {
+	for minLength := 0; minLength <= 30; minLength++ {
+		for _, maxLength := range []int{minLength, minLength + 1, minLength + 5} {
+			max := uint64(maxLength)
+			schema := &openapi3.Schema{MinLength: uint64(minLength), MaxLength: &max}
+			for seed := int64(1); seed <= 100; seed++ {
+				s := mock.NewGenerator(seed).String(schema)
+				if len(s) < minLength || len(s) > maxLength || strings.TrimSpace(s) != s {
+					t.Fatalf("got %q of seed %d, want length between %d and %d without surrounding spaces", s, seed, minLength, maxLength)
+				}
+			}
+		}
+	}
+}
+
+func newMockSchema(t *testing.T, seed int64) graphql.Schema {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	schema, err := graphql.NewSchema(oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{
+		Mock:     true,
+		MockSeed: seed,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func findUsers(t *testing.T, schema graphql.Schema) []User {
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: usersQuery})
+	if result.HasErrors() {
+		t.Fatal(result.Errors)
+	}
+
+	var data struct {
+		FindUsers []User `json:"findUsers"`
+	}
+	encoded, _ := json.Marshal(result.Data)
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		t.Fatal(err)
+	}
+	return data.FindUsers
+}
@@ -0,0 +1,529 @@
+package oas4
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	oas_utils "openapi-to-graphql/oas_utils"
+	"openapi-to-graphql/types"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/graphql-go/graphql"
+)
+
+type TestCase struct {
+	name         string
+	query        string
+	expectedJson string
+}
+
+var enumCases = []TestCase{
+	findTasksByEnums,
+	createTaskWithEnums,
+}
+
+func TestEnums(t *testing.T) {
+	upstream := httptest.NewServer(NewTestServer())
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{ServerURL: upstream.URL})
+
+	runCases(t, config, enumCases)
+
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// values of graphql-go enum are not ordered, descriptions are compared by name
+	expected := map[string]map[string]string{
+		"Status":   {"IN_PROGRESS": "", "DONE": "", "_2XX": "", "EMPTY": "", "DONE2": ""},
+		"Priority": {"LOW": "Some day", "MEDIUM": "This week", "HIGH": "Today"},
+		"Weight":   {"_0_5": "", "_1_5": "", "NEGATIVE_1": "Unknown"},
+		"Flagged":  {"TRUE": "", "FALSE": ""},
+	}
+	for name, want := range expected {
+		enum, ok := schema.Type(name).(*graphql.Enum)
+		if !ok {
+			t.Fatalf("%s is not an enum", name)
+		}
+		got := make(map[string]string)
+		for _, value := range enum.Values() {
+			got[value.Name] = value.Description
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+}
+
+var propertyNameCases = []TestCase{
+	findTasksByRenamedFilter,
+	createTaskWithRenamedFields,
+}
+
+func TestPropertyNames(t *testing.T) {
+	upstream := httptest.NewServer(NewTestServer())
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{ServerURL: upstream.URL})
+
+	runCases(t, config, propertyNameCases)
+}
+
+var idTypeCases = []TestCase{
+	findTasksWithIDs,
+	getTaskByID,
+	createTaskWithID,
+}
+
+func TestIDTypes(t *testing.T) {
+	upstream := httptest.NewServer(NewTestServer())
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{ServerURL: upstream.URL, IDTypes: true})
+
+	runCases(t, config, idTypeCases)
+}
+
+var serverCases = []TestCase{
+	findTasksWithIDs,
+	getTaskByServerOfPath,
+}
+
+func TestServers(t *testing.T) {
+	upstream := httptest.NewServer(NewTestServer())
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	upstreamURL, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := types.Options{
+		IDTypes:         true,
+		ServerVariables: map[string]string{"host": upstreamURL.Hostname(), "port": upstreamURL.Port()},
+	}
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, options)
+
+	runCases(t, config, serverCases)
+}
+
+func TestServerUrl(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	serverUrls := map[string]types.Options{
+		"http://localhost:3000":    {},
+		"http://127.0.0.1:8080":    {ServerVariables: map[string]string{"host": "127.0.0.1", "port": "8080"}},
+		"https://example.com/api":  {ServerIndex: 1, SpecURL: "https://example.com/specs/tasks.json"},
+		"http://localhost:4000/v1": {ServerURL: "http://localhost:4000/v1/"},
+	}
+	for want, options := range serverUrls {
+		got, err := oas_utils.GetServerUrl(public, options)
+		if err != nil || got != want {
+			t.Errorf("got %s, %v, want %s", got, err, want)
+		}
+	}
+
+	errors := map[string]types.Options{
+		"Value example.com of server variable host is not one of localhost, 127.0.0.1": {ServerVariables: map[string]string{"host": "example.com"}},
+		"Server URL /api is relative, spec URL or server URL is required":              {ServerIndex: 1},
+		"Server 2 not found, spec has 2 servers":                                       {ServerIndex: 2},
+	}
+	for want, options := range errors {
+		if _, err := oas_utils.GetServerUrl(public, options); err == nil || err.Error() != want {
+			t.Errorf("got %v, want %s", err, want)
+		}
+	}
+
+	if _, err := oas_utils.GetServerUrl(&openapi3.T{}, types.Options{}); err == nil {
+		t.Error("got no error of spec without servers")
+	}
+
+	// operation without server of its own fails translation of spec without servers
+	withoutServers := *public
+	withoutServers.Servers = nil
+	if _, err := oas_utils.TranslateToSchemaConfigE(&withoutServers, types.Options{}); err == nil || err.Error() != "Server URL not found" {
+		t.Errorf("got %v, want Server URL not found", err)
+	}
+	if _, err := oas_utils.MergeSchemaConfigs([]oas_utils.Spec{{Name: "tasks", OAS: &withoutServers}}); err == nil || !strings.Contains(err.Error(), "Server URL not found") {
+		t.Errorf("got %v of merged spec, want Server URL not found", err)
+	}
+
+	// servers of path override servers of spec
+	operations, err := oas_utils.TranslateOperations(public, types.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, operation := range operations {
+		if operation.OperationName == "getTask" && operation.ServerUrl != "http://localhost:3000/v2" {
+			t.Errorf("got server %s of getTask, want http://localhost:3000/v2", operation.ServerUrl)
+		}
+	}
+}
+
+// Changed spec is translated to the same type names, e.g. on reload of watched spec
+func TestRetranslate(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if _, err := graphql.NewSchema(oas_utils.TranslateToSchemaConfig(public)); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	changed.Components.Schemas["Task"].Value.Properties["note"] = openapi3.NewStringSchema().NewRef()
+	schema, err := graphql.NewSchema(oas_utils.TranslateToSchemaConfig(changed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task, ok := schema.Type("Task").(*graphql.Object)
+	if !ok {
+		t.Fatal("Task is not an object")
+	}
+	if _, ok := task.Fields()["note"]; !ok {
+		t.Error("got no note field of changed Task")
+	}
+	if schema.Type("Task2") != nil {
+		t.Error("got Task2 type of changed Task")
+	}
+}
+
+// Page object can't be paginated inside result union, operation is reported
+func TestPaginationWithErrorUnions(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromData([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "pages", "version": "1.0.0"},
+		"servers": [{"url": "http://localhost:3000"}],
+		"paths": {
+			"/tasks/page": {
+				"get": {
+					"operationId": "findTaskPage",
+					"x-graphql-pagination": {"style": "cursor", "cursorParam": "page_token", "itemsField": "items", "nextCursorField": "next"},
+					"parameters": [{"name": "page_token", "in": "query", "schema": {"type": "string"}}],
+					"responses": {
+						"200": {"description": "page", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/TaskPage"}}}},
+						"404": {"description": "not found", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+					}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"TaskPage": {"type": "object", "properties": {"items": {"type": "array", "items": {"$ref": "#/components/schemas/PageItem"}}, "next": {"type": "string"}}},
+				"PageItem": {"type": "object", "properties": {"title": {"type": "string"}}},
+				"Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := &types.Report{}
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{Pagination: true, ErrorUnions: true, Report: report})
+
+	field := config.Query.Fields()["findTaskPage"]
+	if name := graphql.GetNullable(field.Type).(graphql.Type).Name(); name != "FindTaskPageResult" {
+		t.Errorf("got %s type, want FindTaskPageResult", name)
+	}
+	operations := report.Filter(types.OperationTranslated)
+	if len(operations) != 1 || operations[0].Reason != "pagination is disabled by error unions" {
+		t.Errorf("got report %s", report)
+	}
+
+	config = oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{Pagination: true})
+	if name := graphql.GetNullable(config.Query.Fields()["findTaskPage"].Type).(graphql.Type).Name(); name != "PageItemConnection" {
+		t.Errorf("got %s type without error unions, want PageItemConnection", name)
+	}
+}
+
+// Parameter of the first known name is paging argument, e.g. limit is page size of operation with size and limit parameters
+func TestPaginationArgOrder(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromData([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "pages", "version": "1.0.0"},
+		"servers": [{"url": "http://localhost:3000"}],
+		"paths": {
+			"/tasks": {
+				"get": {
+					"operationId": "findTasks",
+					"parameters": [
+						{"name": "size", "in": "query", "schema": {"type": "integer"}},
+						{"name": "offset", "in": "query", "schema": {"type": "integer"}},
+						{"name": "limit", "in": "query", "schema": {"type": "integer"}}
+					],
+					"responses": {
+						"200": {"description": "tasks", "content": {"application/json": {"schema": {"type": "array", "items": {"type": "string"}}}}}
+					}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{Pagination: true})
+		args := make([]string, 0)
+		for _, arg := range config.Query.Fields()["findTasks"].Args {
+			args = append(args, arg.Name())
+		}
+		sort.Strings(args)
+		if strings.Join(args, ",") != "after,first,size" {
+			t.Fatalf("got arguments %v of connection, want limit and offset replaced by first and after", args)
+		}
+	}
+}
+
+// 204 No Content is translated to Boolean, other success response without content is skipped
+func TestNoContentResponses(t *testing.T) {
+	public, err := openapi3.NewLoader().LoadFromData([]byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "no content", "version": "1.0.0"},
+		"servers": [{"url": "http://localhost:3000"}],
+		"paths": {
+			"/tasks/{id}": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+				"get": {
+					"operationId": "getTask",
+					"responses": {"200": {"description": "task without documented body"}}
+				},
+				"delete": {
+					"operationId": "deleteTask",
+					"responses": {"204": {"description": "deleted"}}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := &types.Report{}
+	config := oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{Report: report})
+
+	if field, ok := config.Mutation.Fields()["deleteTask"]; !ok || field.Type != graphql.Boolean {
+		t.Errorf("got deleteTask field %v, want Boolean", field)
+	}
+	skipped := report.Filter(types.OperationSkipped)
+	if len(skipped) != 1 || skipped[0].OperationName != "getTask" || skipped[0].Reason != "response content not found" {
+		t.Errorf("got report %s, want getTask skipped", report)
+	}
+}
+
+// Cancelled GraphQL request cancels upstream call
+func TestCancelUpstream(t *testing.T) {
+	cancelled := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(cancelled)
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer upstream.Close()
+
+	public, err := openapi3.NewLoader().LoadFromFile("./spec.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	schema, err := graphql.NewSchema(oas_utils.TranslateToSchemaConfigWithOptions(public, types.Options{ServerURL: upstream.URL}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	r := graphql.Do(graphql.Params{Schema: schema, RequestString: "{ findTasks { title } }", Context: ctx})
+	if len(r.Errors) == 0 {
+		t.Error("got no error of cancelled request")
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("upstream request is not cancelled")
+	}
+}
+
+func runCases(t *testing.T, config graphql.SchemaConfig, cases []TestCase) {
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := graphql.Params{Schema: schema, RequestString: tc.query}
+			r := graphql.Do(params)
+
+			if len(r.Errors) > 0 {
+				t.Fatal(r.Errors)
+			}
+
+			got, err := json.Marshal(r)
+			if err != nil {
+				t.Fatalf("got: invalid JSON: %s", err)
+			}
+			want, err := formatJSON([]byte(tc.expectedJson))
+			if err != nil {
+				t.Fatalf("want: invalid JSON: %s", err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Logf("got:  %s", got)
+				t.Logf("want: %s", want)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func formatJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	formatted, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return formatted, nil
+}
+
+var findTasksByEnums = TestCase{
+	name: "findTasks by enums",
+	query: `{
+		findTasks(status: IN_PROGRESS, priority: HIGH) {
+			title
+			status
+			priority
+			weight
+			flagged
+		}
+	}`,
+	expectedJson: `{"data":{"findTasks":[{"flagged":"TRUE","priority":"HIGH","status":"IN_PROGRESS","title":"write tests","weight":"_1_5"}]}}`,
+}
+
+var createTaskWithEnums = TestCase{
+	name: "createTask with enums",
+	query: `mutation {
+		createTask(task: { title: "deploy", status: _2XX, priority: LOW, weight: NEGATIVE_1, flagged: FALSE }) {
+			id
+			status
+			priority
+			weight
+			flagged
+		}
+	}`,
+	expectedJson: `{"data":{"createTask":{"flagged":"FALSE","id":3,"priority":"LOW","status":"_2XX","weight":"NEGATIVE_1"}}}`,
+}
+
+var findTasksByRenamedFilter = TestCase{
+	name: "findTasks by filter with renamed fields",
+	query: `{
+		findTasks(filter: { min_priority: 2, created_after: "2021-02-01" }) {
+			title
+			created_at
+			_type
+			_type2
+		}
+	}`,
+	expectedJson: `{"data":{"findTasks":[{"_type":"chore","_type2":"Task","created_at":"2021-03-01","title":"write tests"}]}}`,
+}
+
+var createTaskWithRenamedFields = TestCase{
+	name: "createTask with renamed fields",
+	query: `mutation {
+		createTask(task: { title: "plan", created_at: "2021-04-01", _type2: "Milestone" }) {
+			id
+			created_at
+			_type
+			_type2
+		}
+	}`,
+	expectedJson: `{"data":{"createTask":{"_type":null,"_type2":"Milestone","created_at":"2021-04-01","id":3}}}`,
+}
+
+var findTasksWithIDs = TestCase{
+	name: "findTasks with IDs",
+	query: `{
+		findTasks(priority: LOW) {
+			id
+			title
+		}
+	}`,
+	expectedJson: `{"data":{"findTasks":[{"id":"2","title":"release"}]}}`,
+}
+
+var getTaskByID = TestCase{
+	name: "getTask by ID",
+	query: `{
+		getTask(taskId: "1") {
+			id
+			title
+		}
+	}`,
+	expectedJson: `{"data":{"getTask":{"id":"1","title":"write tests"}}}`,
+}
+
+// upstream rejects id which is not integer
+var createTaskWithID = TestCase{
+	name: "createTask with ID",
+	query: `mutation {
+		createTask(task: { id: "7", title: "plan", status: DONE }) {
+			id
+			title
+		}
+	}`,
+	expectedJson: `{"data":{"createTask":{"id":"3","title":"plan"}}}`,
+}
+
+var getTaskByServerOfPath = TestCase{
+	name: "getTask by server of path",
+	query: `{
+		getTask(taskId: "2") {
+			id
+			title
+		}
+	}`,
+	expectedJson: `{"data":{"getTask":{"id":"2","title":"release"}}}`,
+}
@@ -0,0 +1,100 @@
+package oas4
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Fields are typed, request with value of another JSON type is rejected
+type Task struct {
+	Id       int      `json:"id"`
+	Title    string   `json:"title"`
+	Status   *string  `json:"status,omitempty"`
+	Priority *int     `json:"priority,omitempty"`
+	Weight   *float64 `json:"weight,omitempty"`
+	Flagged  *bool    `json:"flagged,omitempty"`
+	// property names which are not valid GraphQL names
+	CreatedAt string `json:"created-at,omitempty"`
+	AtType    string `json:"@type,omitempty"`
+	Type      string `json:"_type,omitempty"`
+}
+
+type testServer struct {
+	mu    sync.Mutex
+	tasks []Task
+}
+
+func NewTestServer() http.Handler {
+	router := gin.New()
+	inProgress, high, weight, flagged := "in-progress", 3, 1.5, true
+	done, low := "2xx", 1
+	server := &testServer{tasks: []Task{
+		{Id: 1, Title: "write tests", Status: &inProgress, Priority: &high, Weight: &weight, Flagged: &flagged, CreatedAt: "2021-03-01", AtType: "Task", Type: "chore"},
+		{Id: 2, Title: "release", Status: &done, Priority: &low, CreatedAt: "2021-01-15"},
+	}}
+
+	router.GET("/tasks", server.findTasksHandler)
+	router.POST("/tasks", server.createTaskHandler)
+	router.GET("/tasks/:taskId", server.getTaskHandler)
+	router.GET("/v2/tasks/:taskId", server.getTaskHandler)
+	return router
+}
+
+func (s *testServer) findTasksHandler(c *gin.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Task, 0)
+	for _, task := range s.tasks {
+		if status, ok := c.GetQuery("status"); ok && (task.Status == nil || *task.Status != status) {
+			continue
+		}
+		if priority, ok := c.GetQuery("priority"); ok && (task.Priority == nil || strconv.Itoa(*task.Priority) != priority) {
+			continue
+		}
+		filter := c.QueryMap("filter")
+		if minPriority, ok := filter["min-priority"]; ok && (task.Priority == nil || strconv.Itoa(*task.Priority) < minPriority) {
+			continue
+		}
+		if createdAfter, ok := filter["created-after"]; ok && task.CreatedAt <= createdAfter {
+			continue
+		}
+		result = append(result, task)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (s *testServer) getTaskHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("taskId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, task := range s.tasks {
+		if task.Id == id {
+			c.JSON(http.StatusOK, task)
+			return
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"message": "Task not found"})
+}
+
+func (s *testServer) createTaskHandler(c *gin.Context) {
+	var task Task
+	if err := c.ShouldBindJSON(&task); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task.Id = len(s.tasks) + 1
+	s.tasks = append(s.tasks, task)
+	c.JSON(http.StatusOK, task)
+}
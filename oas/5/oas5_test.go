@@ -0,0 +1,156 @@
+package oas5
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"openapi-to-graphql/loader"
+	oas_utils "openapi-to-graphql/oas_utils"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/graphql-go/graphql"
+)
+
+var specFiles = []string{"spec.yaml", "schemas/book.yaml", "schemas/author.yaml", "schemas/parameters.yaml"}
+
+func TestLoadExternalRefs(t *testing.T) {
+	public, err := loader.Load("./spec.yaml", loader.Options{ExternalRefs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLibrarySchema(t, public)
+}
+
+func TestLoadStdin(t *testing.T) {
+	data, err := ioutil.ReadFile("./spec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// refs are resolved against working directory
+	public, err := loader.Load("-", loader.Options{ExternalRefs: true, Stdin: strings.NewReader(string(data))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLibrarySchema(t, public)
+}
+
+func TestLoadURL(t *testing.T) {
+	upstream := httptest.NewServer(http.FileServer(http.Dir(".")))
+	public, err := loader.Load(upstream.URL+"/spec.yaml", loader.Options{ExternalRefs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLibrarySchema(t, public)
+
+	// local copies are read when URL can't be fetched
+	upstreamURL, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fallbackDir := t.TempDir()
+	for _, file := range specFiles {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(fallbackDir, upstreamURL.Host, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	upstream.Close()
+
+	public, err = loader.Load(upstream.URL+"/spec.yaml", loader.Options{ExternalRefs: true, FallbackDir: fallbackDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLibrarySchema(t, public)
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := map[string]struct {
+		location string
+		options  loader.Options
+	}{
+		"disallowed external reference": {"./spec.yaml", loader.Options{}},
+		"is outside of":                 {"./escape/spec.yaml", loader.Options{ExternalRefs: true}},
+	}
+	for want, c := range cases {
+		if _, err := loader.Load(c.location, c.options); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got %v, want error with %q", c.location, err, want)
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	spec := `
+openapi: 3.0.0
+info:
+  version: 1.0.0
+paths:
+  /books:
+    get:
+      responses:
+        "200":
+          description: Books
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Book"
+components:
+  schemas:
+    Book:
+      type: object
+      properties:
+        id:
+          type: string
+          format: uuid
+`
+	cases := map[bool][]string{
+		// unknown formats are allowed by default
+		false: {"info: value of title must be a non-empty string"},
+		true: {
+			"info: value of title must be a non-empty string",
+			`components.schemas.Book: unsupported 'format' value "uuid"`,
+			`paths./books: unsupported 'format' value "uuid"`,
+		},
+	}
+	for strictFormats, want := range cases {
+		_, err := loader.Load("-", loader.Options{Stdin: strings.NewReader(spec), StrictFormats: strictFormats})
+		var validationError *loader.ValidationError
+		if !errors.As(err, &validationError) {
+			t.Fatalf("got %v, want validation error", err)
+		}
+		if !reflect.DeepEqual(validationError.Errors, want) {
+			t.Errorf("got %q, want %q", validationError.Errors, want)
+		}
+	}
+}
+
+// Book of findBooks is loaded from schemas/book.yaml, Author of Book from schemas/author.yaml
+func assertLibrarySchema(t *testing.T, public *openapi3.T) {
+	schema, err := graphql.NewSchema(oas_utils.TranslateToSchemaConfig(public))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findBooks := schema.QueryType().Fields()["findBooks"]
+	if findBooks == nil || len(findBooks.Args) != 1 || findBooks.Args[0].Name() != "limit" {
+		t.Fatalf("findBooks with limit argument not found")
+	}
+	book, ok := schema.Type("Book").(*graphql.Object)
+	if !ok || book.Fields()["author"] == nil || book.Fields()["author"].Type.Name() != "Author" {
+		t.Fatalf("Book with author field of Author type not found")
+	}
+}
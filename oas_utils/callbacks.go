@@ -0,0 +1,179 @@
+package oas_utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"openapi-to-graphql/subscriptions"
+	typebuilder "openapi-to-graphql/type_builder"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/graphql-go/graphql"
+)
+
+// Runtime expression of callback URL location in operation request, e.g. {$request.body#/callbackUrl} or {$request.query.callbackUrl}
+var callbackExpressionRegexp = regexp.MustCompile(`^\{\$request\.(body#(/[^}]*)|query\.([^}]+))\}$`)
+
+// Returns subscription fields of operation callbacks. Subscribing calls operation with webhook receiver URL as callback URL
+func createCallbackFields(builder *typebuilder.Builder, public *openapi3.T, operation *openapi3.Operation, operationDef *types.OperationDefinition, args graphql.FieldConfigArgument, options types.Options) graphql.Fields {
+	fields := graphql.Fields{}
+
+	callbackNames := make([]string, 0)
+	for name := range operation.Callbacks {
+		callbackNames = append(callbackNames, name)
+	}
+	sort.Strings(callbackNames)
+
+	for _, callbackName := range callbackNames {
+		callback := operation.Callbacks[callbackName]
+		if callback == nil || callback.Value == nil {
+			continue
+		}
+		for expression, pathItem := range *callback.Value {
+			match := callbackExpressionRegexp.FindStringSubmatch(expression)
+			if match == nil {
+				log.Print("Skipping callback " + callbackName + ". Unsupported callback expression " + expression)
+				continue
+			}
+
+			fieldName := utils.ToCamelCase(operationDef.OperationName + " " + callbackName)
+			fields[fieldName] = &graphql.Field{
+				Name:        fieldName,
+				Description: getCallbackDescription(pathItem, operation),
+				Args:        getCallbackArgs(args, operationDef, match[3]),
+				Type:        getCallbackPayloadType(builder, public, pathItem, fieldName, operationDef.Path),
+				Resolve:     subscriptions.Resolver(getCallbackSubscribeFn(operationDef, match[2], match[3], options)),
+			}
+		}
+	}
+
+	return fields
+}
+
+func getCallbackSubscribeFn(operationDef *types.OperationDefinition, bodyPointer string, queryParamName string, options types.Options) subscriptions.SubscribeFn {
+	resolver := GetResolver(getClient(options), operationDef, options)
+
+	return func(ctx context.Context, args map[string]interface{}) (<-chan interface{}, error) {
+		topic, err := newTopic()
+		if err != nil {
+			return nil, err
+		}
+		callbackURL := strings.TrimSuffix(options.WebhookURL, "/") + "/" + topic
+
+		upstreamArgs := make(map[string]interface{})
+		for name, value := range args {
+			upstreamArgs[name] = value
+		}
+
+		if len(bodyPointer) > 0 {
+			argumentName := operationDef.RequestBodyDefinition.ArgumentName
+			if len(argumentName) == 0 {
+				return nil, errors.New("operation has no request body for callback URL")
+			}
+			upstreamArgs[argumentName] = setJSONPointer(upstreamArgs[argumentName], bodyPointer, callbackURL)
+		} else {
+			for argName, param := range operationDef.ArgToParam {
+				if param.Value.In == "query" && param.Value.Name == queryParamName {
+					upstreamArgs[argName] = callbackURL
+				}
+			}
+		}
+
+		// subscribe before operation call, upstream may call back right away
+		events := options.Webhooks.Subscribe(ctx, topic)
+		if _, err := resolver(graphql.ResolveParams{Args: upstreamArgs, Context: ctx}); err != nil {
+			return nil, err
+		}
+		return events, nil
+	}
+}
+
+// Returns operation arguments without the one which holds callback URL
+func getCallbackArgs(args graphql.FieldConfigArgument, operationDef *types.OperationDefinition, queryParamName string) graphql.FieldConfigArgument {
+	callbackArgs := graphql.FieldConfigArgument{}
+	for name, arg := range args {
+		if param, ok := operationDef.ArgToParam[name]; ok && param.Value.In == "query" && param.Value.Name == queryParamName {
+			continue
+		}
+		callbackArgs[name] = arg
+	}
+	return callbackArgs
+}
+
+// Returns type of callback request body sent by upstream, JSON if body has no schema
+func getCallbackPayloadType(builder *typebuilder.Builder, public *openapi3.T, pathItem *openapi3.PathItem, fieldName string, path string) graphql.Type {
+	callbackOperation := getFirstOperation(pathItem)
+	if callbackOperation == nil || callbackOperation.RequestBody == nil || callbackOperation.RequestBody.Value == nil {
+		return typebuilder.JSONScalar
+	}
+	requestContent, err := GetRequestContent(*callbackOperation.RequestBody.Value)
+	if err != nil {
+		return typebuilder.JSONScalar
+	}
+
+	schemaNames := types.SchemaNames{
+		FromSchema: requestContent.Content.Schema.Value.Title,
+		FromRef:    utils.GetRefName(requestContent.Content.Schema.Ref),
+		FromPath:   utils.ToPascalCase(fieldName) + "Payload",
+	}
+	def := builder.CreateDataDefinition(public, requestContent.Content.Schema, schemaNames, path, false)
+	return def.GraphQLType
+}
+
+func getCallbackDescription(pathItem *openapi3.PathItem, operation *openapi3.Operation) string {
+	if callbackOperation := getFirstOperation(pathItem); callbackOperation != nil && len(callbackOperation.Description) > 0 {
+		return callbackOperation.Description
+	}
+	return operation.Description
+}
+
+func getFirstOperation(pathItem *openapi3.PathItem) *openapi3.Operation {
+	if pathItem == nil {
+		return nil
+	}
+	for _, method := range types.HttpMethodsList() {
+		operation, ok := reflect.Indirect(reflect.ValueOf(pathItem)).FieldByName(method).Interface().(*openapi3.Operation)
+		if ok && operation != nil {
+			return operation
+		}
+	}
+	return nil
+}
+
+// Returns copy of object with value set by JSON pointer, e.g. /subscriber/url
+func setJSONPointer(object interface{}, pointer string, value interface{}) interface{} {
+	if len(pointer) == 0 || pointer == "/" {
+		return value
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+
+	current, _ := object.(map[string]interface{})
+	copied := make(map[string]interface{})
+	for k, v := range current {
+		copied[k] = v
+	}
+
+	token := strings.ReplaceAll(strings.ReplaceAll(tokens[0], "~1", "/"), "~0", "~")
+	if len(tokens) == 1 {
+		copied[token] = value
+	} else {
+		copied[token] = setJSONPointer(copied[token], "/"+strings.Join(tokens[1:], "/"), value)
+	}
+	return copied
+}
+
+func newTopic() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,134 @@
+package oas_utils
+
+import (
+	"sort"
+	"strings"
+
+	"openapi-to-graphql/federation"
+	typebuilder "openapi-to-graphql/type_builder"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+
+	"github.com/graphql-go/graphql"
+)
+
+const keyExtension = "x-graphql-key"
+
+// Query operation resolving plain response without unions, wrappers or connections
+type entityOperation struct {
+	definition *types.OperationDefinition
+	resolve    graphql.FieldResolveFn
+}
+
+// Returns entities of response object types with key fields set by options or x-graphql-key extension.
+// Entity is resolved by "get by id" operation returning the object
+func createEntities(operations []*entityOperation, options types.Options) []*federation.Entity {
+	objects := make(map[string]*types.DataDefinition)
+	names := make([]string, 0)
+	for _, operation := range operations {
+		collectObjects(operation.definition.ResponseDefinition, objects, &names)
+	}
+
+	entities := make([]*federation.Entity, 0)
+	for _, name := range names {
+		def := objects[name]
+		key, ok := options.FederationKeys[name]
+		if !ok && !utils.GetExtension(def.Schema.Extensions, keyExtension, &key) {
+			continue
+		}
+		entity := &federation.Entity{
+			Object: typebuilder.GetObjectType(def),
+			Fields: key,
+		}
+		if operation := getEntityOperation(operations, entity); operation != nil {
+			entity.Resolve = getEntityResolver(operation, strings.Fields(key))
+		}
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+func collectObjects(def *types.DataDefinition, objects map[string]*types.DataDefinition, names *[]string) {
+	if def == nil {
+		return
+	}
+	switch def.TargetGraphQLType {
+	case types.Object:
+		if objects[def.GraphQLTypeName] != nil || typebuilder.GetObjectType(def) == nil {
+			return
+		}
+		objects[def.GraphQLTypeName] = def
+		*names = append(*names, def.GraphQLTypeName)
+		properties := make([]string, 0, len(def.ObjectPropertiesDefinitions))
+		for property := range def.ObjectPropertiesDefinitions {
+			properties = append(properties, property)
+		}
+		sort.Strings(properties)
+		for _, property := range properties {
+			collectObjects(def.ObjectPropertiesDefinitions[property], objects, names)
+		}
+	case types.List:
+		collectObjects(def.ListItemDefinitions, objects, names)
+	case types.Union:
+		for _, member := range def.UnionDefinitions {
+			collectObjects(member, objects, names)
+		}
+	}
+}
+
+// Returns GET operation with id-like last path segment returning entity object.
+// Operation of resource inferred from path with entity name is preferred, e.g. /pets/{id} of Pet
+func getEntityOperation(operations []*entityOperation, entity *federation.Entity) *entityOperation {
+	var result *entityOperation
+	for _, operation := range operations {
+		def := operation.definition
+		parts := strings.Split(def.Path, "/")
+		if !strings.EqualFold(def.HttpMethod, "get") || !utils.IsIdParam(parts[len(parts)-1]) || typebuilder.GetObjectType(def.ResponseDefinition) != entity.Object {
+			continue
+		}
+		if getEntityArgs(def, strings.Fields(entity.Fields), nil) == nil {
+			continue
+		}
+		if result == nil || utils.InferResourceNameFromPath(def.Path) == entity.Object.Name() {
+			result = operation
+		}
+	}
+	return result
+}
+
+// Returns arguments of operation from key fields of representation. Key field of the same name is used,
+// id-like last path parameter is taken from the only key field. Returns nil if path parameter has no value
+func getEntityArgs(def *types.OperationDefinition, keyFields []string, representation map[string]interface{}) map[string]interface{} {
+	args := make(map[string]interface{})
+	for arg, param := range def.ArgToParam {
+		field := ""
+		if utils.Contains(keyFields, arg) {
+			field = arg
+		} else if param.Value.In == "path" && len(keyFields) == 1 && strings.HasSuffix(def.Path, "{"+param.Value.Name+"}") {
+			field = keyFields[0]
+		}
+		if len(field) == 0 {
+			if param.Value.In == "path" {
+				return nil
+			}
+			continue
+		}
+		value, ok := representation[field]
+		if !ok {
+			continue
+		}
+		// representations are JSON, e.g. Int key is float64
+		if scalar, ok := graphql.GetNullable(def.ArgDefinitions[arg].InputGraphQLType).(*graphql.Scalar); ok {
+			value = scalar.ParseValue(value)
+		}
+		args[arg] = value
+	}
+	return args
+}
+
+func getEntityResolver(operation *entityOperation, keyFields []string) func(p graphql.ResolveParams, representation map[string]interface{}) (interface{}, error) {
+	return func(p graphql.ResolveParams, representation map[string]interface{}) (interface{}, error) {
+		p.Args = getEntityArgs(operation.definition, keyFields, representation)
+		return operation.resolve(p)
+	}
+}
@@ -0,0 +1,163 @@
+package oas_utils
+
+import (
+	"encoding/base64"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/graphql-go/graphql"
+)
+
+func createFile(contentType string, body []byte, fileURL interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"contentType": contentType,
+		"size":        len(body),
+		"base64":      base64.StdEncoding.EncodeToString(body),
+		"url":         fileURL,
+	}
+}
+
+// Returns URL of file download handler with original parameter names in query, e.g. /files/getPetPhoto?id=1.
+// Operations with request body can't be downloaded by URL
+func getFileURL(p graphql.ResolveParams, operationDef *types.OperationDefinition, options types.Options) interface{} {
+	if len(options.FileDownloadURL) == 0 || len(operationDef.RequestBodyDefinition.ArgumentName) > 0 {
+		return nil
+	}
+
+	query := []string{}
+	for argName, param := range operationDef.ArgToParam {
+		value := p.Args[argName]
+		if value == nil {
+			continue
+		}
+		query = append(query, utils.Serialize(value, param.Value.Name))
+	}
+
+	fileURL := strings.TrimSuffix(options.FileDownloadURL, "/") + "/" + operationDef.OperationName
+	if len(query) > 0 {
+		fileURL += "?" + url.PathEscape(strings.Join(query, "&"))
+	}
+	return fileURL
+}
+
+type fileHandler struct {
+	client     http.Client
+	prefix     string
+	operations map[string]*types.OperationDefinition
+}
+
+// Returns handler which streams binary responses of operations without request body from upstream.
+// Should be served on path of options.FileDownloadURL
+func NewFileHandler(public *openapi3.T, options types.Options) http.Handler {
+	handler := &fileHandler{
+		client:     getClient(options),
+		prefix:     GetFileDownloadPath(options),
+		operations: make(map[string]*types.OperationDefinition),
+	}
+
+	// operations are named in order of translation, names of colliding operations depend on it
+	namer := getNamer(options)
+	specServerUrl, specServerErr := GetServerUrl(public, options)
+	for _, path := range utils.GetPaths(public) {
+		pathItem := public.Paths[path]
+		for _, method := range types.HttpMethodsList() {
+			operation, ok := reflect.Indirect(reflect.ValueOf(pathItem)).FieldByName(method).Interface().(*openapi3.Operation)
+			if !ok || operation == nil {
+				continue
+			}
+			operationName := namer.OperationField(path, method, operation)
+			if operation.RequestBody != nil || len(getFilterReason(path, method, operation, options)) > 0 {
+				continue
+			}
+			_, response, err := GetSuccessResponse(operation.Responses)
+			if err != nil {
+				continue
+			}
+			responseContent, err := GetResponseContent(response)
+			if err != nil || !IsBinaryMediaType(responseContent.ContentType) {
+				continue
+			}
+			serverUrl, err := getOperationServerUrl(pathItem, operation, options)
+			if err != nil {
+				continue
+			}
+			if len(serverUrl) == 0 {
+				if specServerErr != nil {
+					continue
+				}
+				serverUrl = specServerUrl
+			}
+
+			handler.operations[operationName] = &types.OperationDefinition{
+				OperationName:       operationName,
+				OperationID:         operation.OperationID,
+				ServerUrl:           serverUrl,
+				Path:                path,
+				HttpMethod:          method,
+				ResponseContentType: responseContent.ContentType,
+			}
+		}
+	}
+
+	return handler
+}
+
+// Returns path of file download handler with trailing slash, e.g. /files/
+func GetFileDownloadPath(options types.Options) string {
+	return utils.GetURLPath(options.FileDownloadURL)
+}
+
+func (h *fileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	operationDef, ok := h.operations[strings.TrimPrefix(r.URL.Path, h.prefix)]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	// path parameters are taken from query, the rest of query is passed to upstream as is
+	query := r.URL.Query()
+	endpoint := operationDef.Path
+	for name := range query {
+		toReplace := "{" + name + "}"
+		if strings.Contains(endpoint, toReplace) {
+			endpoint = strings.Replace(endpoint, toReplace, url.PathEscape(query.Get(name)), 1)
+			query.Del(name)
+		}
+	}
+	endpoint = operationDef.ServerUrl + endpoint
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(r.Context(), strings.ToUpper(operationDef.HttpMethod), endpoint, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request.Header.Set("Accept", operationDef.ResponseContentType)
+
+	response, err := h.client.Do(request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer response.Body.Close()
+
+	for _, header := range []string{"Content-Type", "Content-Length", "Content-Disposition", "Last-Modified", "ETag"} {
+		if value := response.Header.Get(header); len(value) > 0 {
+			w.Header().Set(header, value)
+		}
+	}
+	w.WriteHeader(response.StatusCode)
+
+	if _, err := io.Copy(w, response.Body); err != nil {
+		log.Print("File download interrupted. " + err.Error())
+	}
+}
@@ -0,0 +1,93 @@
+package oas_utils
+
+import (
+	"log"
+	"regexp"
+	"strings"
+
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const internalExtension = "x-internal"
+
+// Returns reason why operation is filtered out by options.Include and options.Exclude, empty if operation is translated
+func getFilterReason(path string, method string, operation *openapi3.Operation, options types.Options) string {
+	if options.Include != nil {
+		if _, ok := matchSelector(options.Include, path, method, operation); !ok {
+			return "not included"
+		}
+	}
+	if options.Exclude != nil {
+		if rule, ok := matchSelector(options.Exclude, path, method, operation); ok {
+			return "excluded by " + rule
+		}
+	}
+	return ""
+}
+
+// Returns first rule of selector matching operation, e.g. tag admin
+func matchSelector(selector *types.OperationSelector, path string, method string, operation *openapi3.Operation) (string, bool) {
+	for _, tag := range selector.Tags {
+		if utils.Contains(operation.Tags, tag) {
+			return "tag " + tag, true
+		}
+	}
+	for _, glob := range selector.Paths {
+		if matchPathGlob(glob, path) {
+			return "path " + glob, true
+		}
+	}
+	for _, m := range selector.Methods {
+		if strings.EqualFold(m, method) {
+			return "method " + strings.ToUpper(m), true
+		}
+	}
+	if len(operation.OperationID) > 0 && utils.Contains(selector.OperationIds, operation.OperationID) {
+		return "operationId " + operation.OperationID, true
+	}
+	internal := false
+	if selector.Internal && utils.GetExtension(operation.Extensions, internalExtension, &internal) && internal {
+		return internalExtension, true
+	}
+	return "", false
+}
+
+// Returns true if path matches glob, * matches path segment and ** any number of segments
+func matchPathGlob(glob string, path string) bool {
+	pattern := ""
+	for i, part := range strings.Split(glob, "**") {
+		if i > 0 {
+			pattern += ".*"
+		}
+		for j, segment := range strings.Split(part, "*") {
+			if j > 0 {
+				pattern += "[^/]*"
+			}
+			pattern += regexp.QuoteMeta(segment)
+		}
+	}
+	matched, err := regexp.MatchString("^"+pattern+"$", path)
+	return err == nil && matched
+}
+
+// Adds operation to options.Report
+func reportOperation(options types.Options, path string, method string, operationName string, status string, reason string) {
+	if options.Report == nil {
+		return
+	}
+	options.Report.Add(&types.OperationReport{
+		Path:          path,
+		Method:        method,
+		OperationName: operationName,
+		Status:        status,
+		Reason:        reason,
+	})
+}
+
+func skipOperation(options types.Options, path string, method string, operationName string, reason string) {
+	log.Print("Skipping " + operationName + "." + reason)
+	reportOperation(options, path, method, operationName, types.OperationSkipped, reason)
+}
@@ -0,0 +1,70 @@
+package oas_utils
+
+import (
+	"net/http"
+	typebuilder "openapi-to-graphql/type_builder"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Returns definition of { data, headers, status } type with headers declared on success response
+func createResponseWrapperDefinition(builder *typebuilder.Builder, public *openapi3.T, response openapi3.ResponseRef, operationName string, path string) *types.ResponseWrapperDefinition {
+	typeName := utils.ToPascalCase(operationName)
+	headerNames := make([]string, 0)
+	for name, header := range response.Value.Headers {
+		if header != nil && header.Value != nil && header.Value.Schema != nil {
+			headerNames = append(headerNames, name)
+		}
+	}
+	sort.Strings(headerNames)
+
+	headers := make([]*types.ResponseHeaderDefinition, 0)
+	for _, name := range headerNames {
+		header := response.Value.Headers[name].Value
+		fieldName := utils.ToCamelCase(name)
+		schemaNames := types.SchemaNames{
+			FromRef:    utils.GetRefName(header.Schema.Ref),
+			FromSchema: header.Schema.Value.Title,
+			FromPath:   typeName + utils.ToPascalCase(name),
+		}
+
+		headers = append(headers, &types.ResponseHeaderDefinition{
+			HeaderName:     name,
+			FieldName:      fieldName,
+			DataDefinition: builder.CreateDataDefinition(public, header.Schema, schemaNames, path, header.Required),
+		})
+	}
+
+	return &types.ResponseWrapperDefinition{
+		GraphQLTypeName:        builder.PrefixTypeName(typeName + "Response"),
+		HeadersGraphQLTypeName: builder.PrefixTypeName(typeName + "Headers"),
+		Headers:                headers,
+	}
+}
+
+// Returns values of declared headers casted to header schema types. Array values are comma separated
+func DecodeResponseHeaders(header http.Header, headers []*types.ResponseHeaderDefinition) map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, h := range headers {
+		value := header.Get(h.HeaderName)
+		if len(value) == 0 {
+			continue
+		}
+
+		schema := h.DataDefinition.Schema
+		if schema.Type == "array" && schema.Items != nil && schema.Items.Value != nil {
+			items := make([]interface{}, 0)
+			for _, item := range strings.Split(value, ",") {
+				items = append(items, decodeScalar(item, schema.Items.Value))
+			}
+			values[h.FieldName] = items
+		} else {
+			values[h.FieldName] = decodeScalar(value, schema)
+		}
+	}
+	return values
+}
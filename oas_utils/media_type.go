@@ -0,0 +1,41 @@
+package oas_utils
+
+import (
+	"mime"
+	"strings"
+)
+
+// Returns lower-cased media type without parameters, e.g. application/vnd.api+json for "application/vnd.api+json;charset=utf-8"
+func ParseMediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+	return mediaType
+}
+
+// Checks if media type is application/json or has +json structured syntax suffix, e.g. application/problem+json
+func IsJSONMediaType(contentType string) bool {
+	mediaType := ParseMediaType(contentType)
+	return mediaType == "application/json" || hasStructuredSyntaxSuffix(mediaType, "json")
+}
+
+// Checks if media type is application/xml, text/xml or has +xml structured syntax suffix
+func IsXMLMediaType(contentType string) bool {
+	mediaType := ParseMediaType(contentType)
+	return mediaType == "application/xml" || mediaType == "text/xml" || hasStructuredSyntaxSuffix(mediaType, "xml")
+}
+
+func hasStructuredSyntaxSuffix(mediaType string, suffix string) bool {
+	parts := strings.SplitN(mediaType, "/", 2)
+	return len(parts) == 2 && strings.HasSuffix(parts[1], "+"+suffix)
+}
+
+// Checks if media type can't be decoded to JSON value or text, e.g. application/octet-stream, image/png or text/csv
+func IsBinaryMediaType(contentType string) bool {
+	mediaType := ParseMediaType(contentType)
+	if len(mediaType) == 0 || mediaType == "text/plain" || mediaType == "text/html" {
+		return false
+	}
+	return !IsJSONMediaType(mediaType) && !IsXMLMediaType(mediaType) && mediaType != "application/x-www-form-urlencoded"
+}
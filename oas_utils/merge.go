@@ -0,0 +1,108 @@
+package oas_utils
+
+import (
+	"fmt"
+
+	"openapi-to-graphql/federation"
+	typebuilder "openapi-to-graphql/type_builder"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/graphql-go/graphql"
+)
+
+// OpenAPI document translated to part of merged schema
+type Spec struct {
+	// Name of spec, e.g. petstore. Used as namespace field if Namespace is set
+	Name string
+	OAS  *openapi3.T
+	// Query and mutation fields are grouped in namespace field, e.g. query { petstore { findPets } }
+	Namespace bool
+	// Translation options of spec, e.g. ServerURL, Headers and TypePrefix. Namer of options can't be shared by specs,
+	// it assigns names of operations by method and path
+	Options types.Options
+}
+
+// Returns schema config with root fields of all specs. Root fields of spec without namespace are prefixed
+// with TypePrefix, e.g. billingInvoices. Subscription fields are never namespaced. Federation fields are created once
+// with entities of all federated specs. Returns error if root field names conflict or a spec can't be translated
+func MergeSchemaConfigs(specs []Spec) (graphql.SchemaConfig, error) {
+	merged := rootFields{query: graphql.Fields{}, mutation: graphql.Fields{}, subscription: graphql.Fields{}}
+	// types of all specs are created by one builder, so type names of specs don't conflict
+	builder := typebuilder.NewBuilder()
+	federated := false
+
+	for _, spec := range specs {
+		fields, _, err := translate(builder, spec.OAS, spec.Options)
+		if err != nil {
+			return graphql.SchemaConfig{}, fmt.Errorf("Spec %s can't be translated. %s", spec.Name, err)
+		}
+		if spec.Options.Federation {
+			federated = true
+			merged.entities = append(merged.entities, fields.entities...)
+		}
+		prefix := utils.LowerFirst(utils.ToPascalCase(spec.Options.TypePrefix))
+
+		for _, root := range []struct {
+			name   string
+			fields graphql.Fields
+			target graphql.Fields
+		}{{"Query", fields.query, merged.query}, {"Mutation", fields.mutation, merged.mutation}, {"Subscription", fields.subscription, merged.subscription}} {
+			if len(root.fields) == 0 {
+				continue
+			}
+			if spec.Namespace && root.name != "Subscription" {
+				namespaced := graphql.Fields{}
+				for name, field := range root.fields {
+					if !isFederationField(name) {
+						namespaced[name] = field
+					}
+				}
+				description := ""
+				if spec.OAS.Info != nil {
+					description = spec.OAS.Info.Title
+				}
+				field := createNamespaceField(utils.ToCamelCase(spec.Name), utils.ToPascalCase(spec.Name)+root.name, description, namespaced)
+				if err := addField(root.target, field.Name, field, spec); err != nil {
+					return graphql.SchemaConfig{}, err
+				}
+				continue
+			}
+			for name, field := range root.fields {
+				if isFederationField(name) {
+					continue
+				}
+				if len(prefix) > 0 {
+					name = prefix + utils.ToPascalCase(name)
+					field.Name = name
+				}
+				if err := addField(root.target, name, field, spec); err != nil {
+					return graphql.SchemaConfig{}, err
+				}
+			}
+		}
+	}
+
+	if federated {
+		for name, field := range federation.CreateFields(merged.entities) {
+			if err := addField(merged.query, name, field, Spec{Name: "federation"}); err != nil {
+				return graphql.SchemaConfig{}, err
+			}
+		}
+	}
+
+	return newSchemaConfig(merged), nil
+}
+
+func addField(fields graphql.Fields, name string, field *graphql.Field, spec Spec) error {
+	if _, ok := fields[name]; ok {
+		return fmt.Errorf("Field %s of spec %s conflicts with field of another spec. Set type prefix or namespace of spec", name, spec.Name)
+	}
+	fields[name] = field
+	return nil
+}
+
+func isFederationField(name string) bool {
+	return name == "_service" || name == "_entities"
+}
@@ -0,0 +1,118 @@
+package oas_utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"openapi-to-graphql/mock"
+	"openapi-to-graphql/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/graphql-go/graphql"
+)
+
+// Size of generated binary responses
+const mockFileSize = 64
+
+// Returns resolver which generates success response instead of calling upstream. Response examples are used if present.
+// The same arguments and seed give the same result
+func GetMockResolver(operationDef *types.OperationDefinition, successCode string, responseContent types.ResponseContent, options types.Options) func(p graphql.ResolveParams) (interface{}, error) {
+	statusCode := getMockStatusCode(successCode)
+	example := getMediaTypeExample(responseContent.Content)
+	// there is no upstream to download files from
+	options.FileDownloadURL = ""
+
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		seed, err := getMockSeed(options.MockSeed, operationDef.OperationName, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		generator := mock.NewGenerator(seed)
+
+		response := &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Header:     http.Header{},
+		}
+		var body []byte
+		if !operationDef.NoContent {
+			if IsBinaryMediaType(operationDef.ResponseContentType) {
+				response.Header.Set("Content-Type", operationDef.ResponseContentType)
+				body = generator.Bytes(mockFileSize)
+			} else {
+				value := example
+				if value == nil {
+					value = generator.Value(operationDef.ResponseDefinition)
+				}
+				if body, err = json.Marshal(value); err != nil {
+					return nil, err
+				}
+				response.Header.Set("Content-Type", "application/json")
+			}
+		}
+		if operationDef.ResponseWrapperDefinition != nil {
+			for _, header := range operationDef.ResponseWrapperDefinition.Headers {
+				if value := generator.Value(header.DataDefinition); value != nil {
+					response.Header.Set(header.HeaderName, formatMockHeader(value))
+				}
+			}
+		}
+
+		return resolveResponse(p, operationDef, options, response, body)
+	}
+}
+
+// Returns example of media type or its first named example
+func getMediaTypeExample(mediaType openapi3.MediaType) interface{} {
+	if mediaType.Example != nil {
+		return mediaType.Example
+	}
+	names := make([]string, 0, len(mediaType.Examples))
+	for name, example := range mediaType.Examples {
+		if example != nil && example.Value != nil && example.Value.Value != nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return mediaType.Examples[names[0]].Value.Value
+}
+
+// Returns status code of success response, e.g. 200 for 2XX and default
+func getMockStatusCode(code string) int {
+	if statusCode, err := strconv.Atoi(code); err == nil {
+		return statusCode
+	}
+	return http.StatusOK
+}
+
+// Combines seed with operation and arguments, so results don't depend on order of calls
+func getMockSeed(seed int64, operationName string, args map[string]interface{}) (int64, error) {
+	// map keys are sorted by json encoder, so equal arguments give equal seeds
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return 0, err
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(operationName))
+	hash.Write(encodedArgs)
+	return seed ^ int64(hash.Sum64()), nil
+}
+
+// Array headers are comma separated
+func formatMockHeader(value interface{}) string {
+	if items, ok := value.([]interface{}); ok {
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			values = append(values, fmt.Sprint(item))
+		}
+		return strings.Join(values, ",")
+	}
+	return fmt.Sprint(value)
+}
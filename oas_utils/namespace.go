@@ -0,0 +1,69 @@
+package oas_utils
+
+import (
+	"log"
+	"sort"
+
+	typebuilder "openapi-to-graphql/type_builder"
+	"openapi-to-graphql/utils"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/graphql-go/graphql"
+)
+
+// Returns field of namespace type, e.g. petstore: PetstoreQuery. Namespace resolves to empty object, its fields resolve as root fields
+func createNamespaceField(name string, typeName string, description string, fields graphql.Fields) *graphql.Field {
+	return &graphql.Field{
+		Name:        name,
+		Description: description,
+		Type: graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+			Name:        typeName,
+			Description: description,
+			Fields:      fields,
+		})),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return map[string]interface{}{}, nil
+		},
+	}
+}
+
+// Returns root fields grouped in namespace fields by first tag of operation, e.g. pets: PetsQuery.
+// Description of tag is description of namespace type. Fields without tag stay in root
+func groupFieldsByTag(builder *typebuilder.Builder, public *openapi3.T, fields graphql.Fields, tags map[string]string, rootName string) graphql.Fields {
+	grouped := graphql.Fields{}
+	namespaces := make(map[string]graphql.Fields)
+	for name, field := range fields {
+		tag, ok := tags[name]
+		if !ok {
+			grouped[name] = field
+			continue
+		}
+		if namespaces[tag] == nil {
+			namespaces[tag] = graphql.Fields{}
+		}
+		namespaces[tag][name] = field
+	}
+
+	names := make([]string, 0, len(namespaces))
+	for tag := range namespaces {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+
+	for _, tag := range names {
+		name := utils.ToCamelCase(tag)
+		if _, ok := grouped[name]; ok {
+			log.Print("Skipping namespace " + name + ". Root field with the same name exists")
+			for fieldName, field := range namespaces[tag] {
+				grouped[fieldName] = field
+			}
+			continue
+		}
+		description := ""
+		if t := public.Tags.Get(tag); t != nil {
+			description = t.Description
+		}
+		grouped[name] = createNamespaceField(name, builder.PrefixTypeName(utils.ToPascalCase(tag)+rootName), description, namespaces[tag])
+	}
+	return grouped
+}
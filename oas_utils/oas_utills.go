@@ -10,18 +10,50 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"openapi-to-graphql/federation"
+	"openapi-to-graphql/metrics"
+	"openapi-to-graphql/naming"
+	"openapi-to-graphql/subscriptions"
 	typebuilder "openapi-to-graphql/type_builder"
 	"openapi-to-graphql/types"
 	"openapi-to-graphql/utils"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/graphql-go/graphql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-var client = http.Client{}
+// Returns client of upstream requests, options.Transport is used if set. options.Headers are added to every request
+func getClient(options types.Options) http.Client {
+	if len(options.Headers) > 0 {
+		return http.Client{Transport: &headerTransport{header: options.Headers, next: options.Transport}}
+	}
+	return http.Client{Transport: options.Transport}
+}
+
+// Adds static headers to upstream requests, e.g. Authorization of spec
+type headerTransport struct {
+	header http.Header
+	next   http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request = request.Clone(request.Context())
+	for name, values := range t.header {
+		request.Header[name] = values
+	}
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(request)
+}
 
 type Body struct {
 	ContentType string
@@ -44,110 +76,345 @@ func (b *Body) Encode() io.Reader {
 	return &bytes.Buffer{}
 }
 
-func GetResolver(client http.Client, path string, httpMethod string, argToParam map[string]*openapi3.ParameterRef, requestBodyDef *types.RequestBodyDefinition) func(p graphql.ResolveParams) (interface{}, error) {
+func GetResolver(client http.Client, operationDef *types.OperationDefinition, options types.Options) func(p graphql.ResolveParams) (interface{}, error) {
 	return func(p graphql.ResolveParams) (interface{}, error) {
-		endpoint := ExtractRequestDataFromArgs(p, path, httpMethod, argToParam)
-
-		requestBodyValue := p.Args[requestBodyDef.ArgumentName]
+		ctx, span := startOperationSpan(p.Context, "resolve "+operationDef.OperationName, trace.SpanKindInternal, operationDef)
+		defer span.End()
+		p.Context = ctx
 
-		body := Body{
-			ContentType: requestBodyDef.ContentType,
-			Data:        requestBodyValue,
-		}
-
-		request, err := http.NewRequest(strings.ToUpper(httpMethod), endpoint, body.Encode())
+		response, responseBody, err := doRequest(client, p, operationDef, nil)
 		if err != nil {
+			recordError(span, err)
 			return nil, err
 		}
-
-		if requestBodyDef != nil {
-			request.Header.Set("Content-Type", requestBodyDef.ContentType)
-		}
-
-		response, err := client.Do(request)
+		data, err := resolveResponse(p, operationDef, options, response, responseBody)
 		if err != nil {
-			return nil, err
+			recordError(span, err)
 		}
+		return data, err
+	}
+}
 
-		defer response.Body.Close()
+// Calls upstream operation with field arguments. Header is added to upstream request
+func doRequest(client http.Client, p graphql.ResolveParams, operationDef *types.OperationDefinition, header http.Header) (*http.Response, []byte, error) {
+	requestBodyDef := operationDef.RequestBodyDefinition
+	if operationDef.PaginationDefinition != nil {
+		p.Args = getUpstreamPaginationArgs(p.Args, operationDef.PaginationDefinition)
+	}
+	args := make(map[string]interface{}, len(p.Args))
+	for name, value := range p.Args {
+		args[name] = typebuilder.GetUpstreamValue(operationDef.ArgDefinitions[name], value)
+	}
+	p.Args = args
+	endpoint := ExtractRequestDataFromArgs(p, operationDef.ServerUrl+operationDef.Path, operationDef.HttpMethod, operationDef.ArgToParam)
 
-		responseBody, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			return nil, err
-		}
-		var jsonData interface{}
+	requestBodyValue := p.Args[requestBodyDef.ArgumentName]
+
+	body := Body{
+		ContentType: requestBodyDef.ContentType,
+		Data:        requestBodyValue,
+	}
+
+	// upstream call is cancelled with GraphQL request or subscription
+	request, err := http.NewRequestWithContext(p.Context, strings.ToUpper(operationDef.HttpMethod), endpoint, body.Encode())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if requestBodyDef != nil {
+		request.Header.Set("Content-Type", requestBodyDef.ContentType)
+	}
+	if len(operationDef.ResponseContentType) > 0 {
+		request.Header.Set("Accept", operationDef.ResponseContentType)
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+
+	ctx, span := startOperationSpan(p.Context, strings.ToUpper(operationDef.HttpMethod)+" "+operationDef.Path, trace.SpanKindClient, operationDef)
+	span.SetAttributes(semconv.HTTPURLKey.String(endpoint))
+	injectTraceContext(ctx, request)
 
-		json.Unmarshal(responseBody, &jsonData)
+	// operation without operationId is recorded by path template
+	operationId := operationDef.OperationID
+	if len(operationId) == 0 {
+		operationId = operationDef.Path
+	}
+	start := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		metrics.ObserveUpstreamRequest(operationId, request.Method, 0, time.Since(start))
+		endClientSpan(span, nil, nil, err)
+		return nil, nil, err
+	}
 
-		text := string(responseBody) // have to check response header
+	defer response.Body.Close()
 
-		var data interface{}
+	responseBody, err := ioutil.ReadAll(response.Body)
+	metrics.ObserveUpstreamRequest(operationId, request.Method, response.StatusCode, time.Since(start))
+	endClientSpan(span, response, responseBody, err)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, responseBody, nil
+}
 
-		if jsonData != nil {
-			data = jsonData
-		} else {
-			data = text
+// Returns field value of upstream response
+func resolveResponse(p graphql.ResolveParams, operationDef *types.OperationDefinition, options types.Options, response *http.Response, responseBody []byte) (interface{}, error) {
+	data, err := getResponseData(p, operationDef, options, response, responseBody)
+	if err != nil {
+		return nil, err
+	}
+	if operationDef.PaginationDefinition != nil {
+		data = createConnection(p.Args, data, response.Header, operationDef.PaginationDefinition)
+	}
+
+	if operationDef.ResponseWrapperDefinition != nil {
+		return map[string]interface{}{
+			"data":    data,
+			"headers": DecodeResponseHeaders(response.Header, operationDef.ResponseWrapperDefinition.Headers),
+			"status":  response.StatusCode,
+		}, nil
+	}
+
+	return data, nil
+}
+
+func getResponseData(p graphql.ResolveParams, operationDef *types.OperationDefinition, options types.Options, response *http.Response, responseBody []byte) (interface{}, error) {
+	contentType := response.Header.Get("Content-Type")
+	resultDef := operationDef.ResultDefinition
+
+	if resultDef != nil {
+		if member := getResultMember(resultDef, response.StatusCode); member != nil {
+			if object, ok := DecodeResponseBody(contentType, responseBody, member).(map[string]interface{}); ok {
+				object[typebuilder.ResultTypeKey] = member.GraphQLTypeName
+				return object, nil
+			}
 		}
+	}
 
-		if response.StatusCode >= 400 {
-			err := fmt.Errorf("StatusCode: %v. Status: %v. Response body: %v", response.StatusCode, response.Status, data)
-			return nil, err
+	var data interface{}
+	if IsBinaryMediaType(operationDef.ResponseContentType) && response.StatusCode < 400 {
+		data = createFile(contentType, responseBody, getFileURL(p, operationDef, options))
+	} else {
+		data = DecodeResponseBody(contentType, responseBody, operationDef.ResponseDefinition)
+	}
+
+	if response.StatusCode >= 400 {
+		err := fmt.Errorf("StatusCode: %v. Status: %v. Response body: %v", response.StatusCode, response.Status, data)
+		return nil, err
+	}
+
+	// true when upstream request succeeded, e.g. 204 No Content
+	if operationDef.NoContent {
+		return true, nil
+	}
+
+	return data, nil
+}
+
+// Decodes XML responses with schema of response definition, JSON responses to interface{}, everything else to string
+func DecodeResponseBody(contentType string, body []byte, responseDef *types.DataDefinition) interface{} {
+	if IsXMLMediaType(contentType) && responseDef != nil {
+		data, err := DecodeXML(body, responseDef.Schema)
+		if err == nil {
+			return data
 		}
+	}
 
-		return data, nil
+	var jsonData interface{}
+	json.Unmarshal(body, &jsonData)
+
+	if jsonData != nil {
+		return jsonData
 	}
+	return string(body)
 }
 
+// Returns status code and success response. Priority: 200, 201, other 2XX, default
 func GetSuccessResponse(
 	responses openapi3.Responses,
-) (openapi3.ResponseRef, error) {
+) (string, openapi3.ResponseRef, error) {
+	codes := make([]string, 0)
 	for codeStr, response := range responses {
-		code, err := strconv.Atoi(codeStr)
-		if err == nil && code >= 200 && code < 300 && response != nil {
-			return *response, nil
+		if response == nil || response.Value == nil {
+			continue
+		}
+		if successStatusPriority(codeStr) >= 0 {
+			codes = append(codes, codeStr)
 		}
 	}
-	return openapi3.ResponseRef{}, errors.New("success status code not found")
+	if len(codes) == 0 {
+		return "", openapi3.ResponseRef{}, errors.New("success status code not found")
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		pi, pj := successStatusPriority(codes[i]), successStatusPriority(codes[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return codes[i] < codes[j]
+	})
+
+	return codes[0], *responses[codes[0]], nil
 }
 
-func GetResponseContent(
-	response openapi3.ResponseRef,
-) (openapi3.MediaType, error) {
-	for name, content := range response.Value.Content {
-		if name == "application/json" && content.Schema != nil {
-			return *content, nil
+// Returns -1 if status code is not a success one
+func successStatusPriority(codeStr string) int {
+	code := strings.ToUpper(codeStr)
+	switch {
+	case code == "200":
+		return 0
+	case code == "201":
+		return 1
+	case code == "DEFAULT":
+		return 3
+	case len(code) == 3 && code[0] == '2':
+		if _, err := strconv.Atoi(code); err == nil || code == "2XX" {
+			return 2
 		}
-		if name == "text/plain" && content.Schema != nil {
-			return *content, nil
+	}
+	return -1
+}
+
+// Checks if success response is 204 No Content. Other success responses without content are not translated
+func IsNoContentResponse(codeStr string) bool {
+	return codeStr == "204"
+}
+
+// Returns documented responses with status codes from 4XX, 5XX and default. Sorted by status code, default goes last
+func GetErrorResponses(
+	responses openapi3.Responses,
+) []*types.ErrorResponseDefinition {
+	codes := make([]string, 0)
+	for codeStr, response := range responses {
+		if response == nil || response.Value == nil {
+			continue
+		}
+		if codeStr == "default" || strings.HasPrefix(codeStr, "4") || strings.HasPrefix(codeStr, "5") {
+			codes = append(codes, codeStr)
+		}
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if codes[j] == "default" {
+			return codes[i] != "default"
 		}
-		if name == "text/html" && content.Schema != nil {
-			return *content, nil
+		if codes[i] == "default" {
+			return false
+		}
+		return codes[i] < codes[j]
+	})
+
+	errorResponses := make([]*types.ErrorResponseDefinition, 0)
+	for _, code := range codes {
+		errorResponses = append(errorResponses, &types.ErrorResponseDefinition{StatusCode: code})
+	}
+	return errorResponses
+}
+
+// Returns result member for status code. Exact code has priority over range (e.g. 4XX), range over default
+func getResultMember(resultDef *types.ResultDefinition, statusCode int) *types.DataDefinition {
+	if statusCode >= 200 && statusCode < 300 {
+		return resultDef.Success
+	}
+
+	code := strconv.Itoa(statusCode)
+	statusRange := code[:1] + "XX"
+	var rangeMember, defaultMember *types.DataDefinition
+
+	for _, e := range resultDef.Errors {
+		switch strings.ToUpper(e.StatusCode) {
+		case code:
+			return e.DataDefinition
+		case statusRange:
+			rangeMember = e.DataDefinition
+		case "DEFAULT":
+			defaultMember = e.DataDefinition
 		}
 	}
-	return openapi3.MediaType{}, errors.New("response content not found")
+
+	if rangeMember != nil {
+		return rangeMember
+	}
+	return defaultMember
+}
+
+// Returns response content. Priority: application/json, other JSON media types, XML, text
+func GetResponseContent(
+	response openapi3.ResponseRef,
+) (types.ResponseContent, error) {
+	names := sortedContentTypes(response.Value.Content, responseContentTypePriority)
+	if len(names) == 0 {
+		return types.ResponseContent{}, errors.New("response content not found")
+	}
+	return types.ResponseContent{ContentType: names[0], Content: *response.Value.Content[names[0]]}, nil
 }
 
 func GetRequestContent(
 	request openapi3.RequestBody,
 ) (types.RequestContent, error) {
-	for name, content := range request.Content {
-		if name == "application/json" && content.Schema != nil {
-			return types.RequestContent{ContentType: name, Content: *content}, nil
+	names := sortedContentTypes(request.Content, requestContentTypePriority)
+	if len(names) == 0 {
+		return types.RequestContent{}, errors.New("request content not found")
+	}
+	return types.RequestContent{ContentType: names[0], Content: *request.Content[names[0]]}, nil
+}
+
+// Returns supported content types with schema sorted by priority. Binary content types don't need schema
+func sortedContentTypes(content openapi3.Content, priority func(string) int) []string {
+	names := make([]string, 0)
+	for name, mediaType := range content {
+		if mediaType != nil && (mediaType.Schema != nil || IsBinaryMediaType(name)) && priority(name) >= 0 {
+			names = append(names, name)
 		}
-		if name == "application/x-www-form-urlencoded" && content.Schema != nil {
-			return types.RequestContent{ContentType: name, Content: *content}, nil
+	}
+	sort.Slice(names, func(i, j int) bool {
+		pi, pj := priority(names[i]), priority(names[j])
+		if pi != pj {
+			return pi < pj
 		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Returns -1 if content type is not supported
+func responseContentTypePriority(name string) int {
+	mediaType := ParseMediaType(name)
+	switch {
+	case mediaType == "application/json":
+		return 0
+	case IsJSONMediaType(mediaType):
+		return 1
+	case IsXMLMediaType(mediaType):
+		return 2
+	case mediaType == "text/plain":
+		return 3
+	case mediaType == "text/html":
+		return 4
+	case IsBinaryMediaType(mediaType):
+		return 5
+	}
+	return -1
+}
+
+func requestContentTypePriority(name string) int {
+	switch ParseMediaType(name) {
+	case "application/json":
+		return 0
+	case "application/x-www-form-urlencoded":
+		return 1
 	}
-	return types.RequestContent{}, errors.New("request content not found")
+	return -1
 }
 
 func ExtractRequestDataFromArgs(p graphql.ResolveParams, path string, httpMethod string, argToParam map[string]*openapi3.ParameterRef) string {
 	endpoint := path
 	queryString := []string{}
 
-	for _, param := range argToParam {
+	for argName, param := range argToParam {
 		name := param.Value.Name
-		value := p.Args[name]
+		value := p.Args[argName]
 
 		if value == nil {
 			continue
@@ -172,43 +439,140 @@ func ExtractRequestDataFromArgs(p graphql.ResolveParams, path string, httpMethod
 	return endpoint
 }
 
+// Returns field name of operation. Inferred from path if operationId is not set
+func GetOperationName(path string, operation *openapi3.Operation) string {
+	operationName := operation.OperationID
+	if len(operationName) == 0 {
+		operationName = utils.InferResourceNameFromPath(path)
+	}
+	return utils.ToCamelCase(operationName)
+}
+
 func TranslateToSchemaConfig(public *openapi3.T) graphql.SchemaConfig {
-	serverUrl := utils.GetServerUrl(public)
+	return TranslateToSchemaConfigWithOptions(public, types.Options{})
+}
+
+// Returns schema config of spec. Translation error is logged and empty config is returned, use TranslateToSchemaConfigE to get it
+func TranslateToSchemaConfigWithOptions(public *openapi3.T, options types.Options) graphql.SchemaConfig {
+	config, err := TranslateToSchemaConfigE(public, options)
+	if err != nil {
+		log.Print("Translation failed. " + err.Error())
+	}
+	return config
+}
+
+// Returns schema config of spec or error if spec can't be translated, e.g. server of spec is missing
+func TranslateToSchemaConfigE(public *openapi3.T, options types.Options) (graphql.SchemaConfig, error) {
+	fields, _, err := translate(typebuilder.NewBuilder(), public, options)
+	if err != nil {
+		return graphql.SchemaConfig{}, err
+	}
+	return newSchemaConfig(fields), nil
+}
+
+// Returns definitions of operations translated to root fields, sorted by operation name
+func TranslateOperations(public *openapi3.T, options types.Options) ([]*types.OperationDefinition, error) {
+	_, operations, err := translate(typebuilder.NewBuilder(), public, options)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].OperationName < operations[j].OperationName
+	})
+	return operations, nil
+}
+
+// Returns options.Namer, or new namer of camel case strategy
+func getNamer(options types.Options) types.Namer {
+	if options.Namer != nil {
+		return options.Namer
+	}
+	return naming.NewDefaultNamer()
+}
+
+// Fields of root types translated from spec
+type rootFields struct {
+	query        graphql.Fields
+	mutation     graphql.Fields
+	subscription graphql.Fields
+	// Entities of _entities query field, set if federation is enabled
+	entities []*federation.Entity
+}
+
+// Returns error if an operation uses server of spec and it can't be resolved
+func translate(builder *typebuilder.Builder, public *openapi3.T, options types.Options) (rootFields, []*types.OperationDefinition, error) {
+	operations := make([]*types.OperationDefinition, 0)
+	namer := getNamer(options)
+	builder.SetTypePrefix(options.TypePrefix)
+	builder.SetNamer(namer)
+	builder.SetIDTypes(options.IDTypes)
+	// collisions of namer reused by previous translation are already reported
+	recorder, records := namer.(types.CollisionRecorder)
+	reported := 0
+	if records {
+		reported = len(recorder.Collisions())
+	}
 
 	queryFields := graphql.Fields{}
 	mutationFields := graphql.Fields{}
-
-	for path, pathItem := range public.Paths {
+	subscriptionFields := graphql.Fields{}
+	poller := subscriptions.NewPoller(options.PollInterval)
+	entityOperations := make([]*entityOperation, 0)
+	// server of spec is resolved once, its error fails translation only if an operation has no server of its own
+	specServerUrl, specServerErr := GetServerUrl(public, options)
+	// first tag of root field
+	fieldTags := make(map[string]string)
+
+	// paths are sorted, names of types depend on order of translation
+	for _, path := range utils.GetPaths(public) {
+		pathItem := public.Paths[path]
 		for _, method := range types.HttpMethodsList() {
 			// iterate through struct fields
 			operation, ok := reflect.Indirect(reflect.ValueOf(pathItem)).FieldByName(method).Interface().(*openapi3.Operation)
 			if !ok || operation == nil {
 				continue
 			}
-			operationName := operation.OperationID
-			if len(operationName) == 0 {
-				operationName = utils.InferResourceNameFromPath(path)
+			operationName := namer.OperationField(path, method, operation)
+			if reason := getFilterReason(path, method, operation, options); len(reason) > 0 {
+				log.Print("Filtering out " + operationName + ". " + reason)
+				reportOperation(options, path, method, operationName, types.OperationFiltered, reason)
+				continue
 			}
-			operationName = utils.ToCamelCase(operationName)
 
-			httpMethod, err := types.GetHttpMethod(method)
+			serverUrl, err := getOperationServerUrl(pathItem, operation, options)
 			if err != nil {
-				log.Print("Skipping " + operationName + "." + err.Error())
+				skipOperation(options, path, method, operationName, err.Error())
 				continue
 			}
+			if len(serverUrl) == 0 {
+				if specServerErr != nil {
+					return rootFields{}, nil, specServerErr
+				}
+				serverUrl = specServerUrl
+			}
 
-			response, err := GetSuccessResponse(operation.Responses)
+			httpMethod, err := types.GetHttpMethod(method)
 			if err != nil {
-				log.Print("Skipping " + operationName + "." + err.Error())
+				skipOperation(options, path, method, operationName, err.Error())
 				continue
 			}
 
-			responseContent, err := GetResponseContent(response)
+			successCode, response, err := GetSuccessResponse(operation.Responses)
 			if err != nil {
-				log.Print("Skipping " + operationName + "." + err.Error())
+				skipOperation(options, path, method, operationName, err.Error())
 				continue
 			}
 
+			noContent := IsNoContentResponse(successCode)
+			responseContent := types.ResponseContent{Content: openapi3.MediaType{Schema: openapi3.NewBoolSchema().NewRef()}}
+			if !noContent {
+				responseContent, err = GetResponseContent(response)
+				if err != nil {
+					skipOperation(options, path, method, operationName, err.Error())
+					continue
+				}
+			}
+
 			operationType := types.Query
 			if httpMethod != types.HttpMethod.Get {
 				operationType = types.Mutation
@@ -217,10 +581,11 @@ func TranslateToSchemaConfig(public *openapi3.T) graphql.SchemaConfig {
 			args := graphql.FieldConfigArgument{}
 			// map of arg sane name to parameter
 			argToParam := make(map[string]*openapi3.ParameterRef)
+			argDefinitions := make(map[string]*types.DataDefinition)
 
 			for _, parameter := range operation.Parameters {
 				p := parameter.Value
-				name := utils.ToCamelCase(p.Name)
+				name := namer.Argument(operationName, p.Name, p.Extensions)
 				description := p.Description
 
 				names := types.SchemaNames{
@@ -234,7 +599,8 @@ func TranslateToSchemaConfig(public *openapi3.T) graphql.SchemaConfig {
 					log.Print("Skipping " + operationName + "." + "Parameter schema not found")
 					continue
 				}
-				def := typebuilder.CreateDataDefinition(public, schema, names, path, p.Required)
+				def := builder.CreateDataDefinition(public, schema, names, path, p.Required)
+				builder.AssignIDType(def, p.Name)
 
 				args[name] = &graphql.ArgumentConfig{
 					Type:        def.InputGraphQLType,
@@ -242,6 +608,7 @@ func TranslateToSchemaConfig(public *openapi3.T) graphql.SchemaConfig {
 				}
 
 				argToParam[name] = parameter
+				argDefinitions[name] = def
 			}
 
 			var requestContentDefinition types.RequestBodyDefinition
@@ -252,7 +619,7 @@ func TranslateToSchemaConfig(public *openapi3.T) graphql.SchemaConfig {
 				required := requestBody.Value.Required
 				requestContent, err := GetRequestContent(*requestBody.Value)
 				if err != nil {
-					log.Print("Skipping " + operationName + "." + err.Error())
+					skipOperation(options, path, method, operationName, err.Error())
 					continue
 				}
 
@@ -262,14 +629,15 @@ func TranslateToSchemaConfig(public *openapi3.T) graphql.SchemaConfig {
 					FromPath:   utils.InferResourceNameFromPath(path),
 				}
 
-				def := typebuilder.CreateDataDefinition(public, requestContent.Content.Schema, schemaNames, path, required)
+				def := builder.CreateDataDefinition(public, requestContent.Content.Schema, schemaNames, path, required)
 
-				argumentName := utils.ToCamelCase(def.GraphQLInputTypeName)
+				argumentName := namer.Argument(operationName, def.GraphQLInputTypeName, requestBody.Value.Extensions)
 
 				args[argumentName] = &graphql.ArgumentConfig{ // should be astraction, with simple data definition, not argument config
 					Type:        def.InputGraphQLType,
 					Description: description,
 				}
+				argDefinitions[argumentName] = def
 				requestContentDefinition = types.RequestBodyDefinition{
 					ContentType:    requestContent.ContentType,
 					ArgumentName:   argumentName,
@@ -277,47 +645,195 @@ func TranslateToSchemaConfig(public *openapi3.T) graphql.SchemaConfig {
 				}
 			}
 
-			schemaNames := types.SchemaNames{
-				FromSchema: responseContent.Schema.Value.Title,
-				FromRef:    utils.GetRefName(responseContent.Schema.Ref),
-				FromPath:   utils.InferResourceNameFromPath(path),
+			var def *types.DataDefinition
+			if IsBinaryMediaType(responseContent.ContentType) {
+				def = typebuilder.CreateFileDefinition(public, responseContent.Content.Schema, path)
+			} else {
+				schemaNames := types.SchemaNames{
+					FromSchema: responseContent.Content.Schema.Value.Title,
+					FromRef:    utils.GetRefName(responseContent.Content.Schema.Ref),
+					FromPath:   utils.InferResourceNameFromPath(path),
+				}
+				def = builder.CreateDataDefinition(public, responseContent.Content.Schema, schemaNames, path, false)
+			}
+			fieldType := def.GraphQLType
+
+			var resultDefinition *types.ResultDefinition
+			if options.ErrorUnions {
+				resultDefinition = createResultDefinition(builder, public, operation, operationName, path, successCode, def)
+				if resultDefinition != nil {
+					fieldType = builder.CreateResultUnion(resultDefinition)
+				}
 			}
 
-			def := typebuilder.CreateDataDefinition(public, responseContent.Schema, schemaNames, path, false)
-			resolver := GetResolver(client, serverUrl+path, method, argToParam, &requestContentDefinition)
+			operationDefinition := &types.OperationDefinition{
+				OperationName:         operationName,
+				OperationID:           operation.OperationID,
+				Description:           operation.Description,
+				OperationType:         operationType,
+				ServerUrl:             serverUrl,
+				Path:                  path,
+				HttpMethod:            method,
+				ArgToParam:            argToParam,
+				ArgDefinitions:        argDefinitions,
+				RequestBodyDefinition: &requestContentDefinition,
+				ResponseContentType:   responseContent.ContentType,
+				ResponseDefinition:    def,
+				ResultDefinition:      resultDefinition,
+				NoContent:             noContent,
+			}
+			// note of translated operation, e.g. feature disabled by other option
+			note := ""
+			if options.Pagination && httpMethod == types.HttpMethod.Get {
+				pagination := createPaginationDefinition(operation, argToParam, def)
+				if pagination != nil && resultDefinition != nil {
+					// connection is not a member of result union, error responses take precedence
+					note = "pagination is disabled by error unions"
+					log.Print("Not paginating " + operationName + ". Result union of error responses is returned")
+				} else if pagination != nil {
+					operationDefinition.PaginationDefinition = pagination
+					args = getConnectionArgs(args, pagination)
+					fieldType = builder.CreateConnection(pagination)
+				}
+			}
+			if options.ResponseWrappers {
+				operationDefinition.ResponseWrapperDefinition = createResponseWrapperDefinition(builder, public, response, operationName, path)
+				fieldType = builder.CreateResponseWrapper(operationDefinition.ResponseWrapperDefinition, fieldType)
+			}
+			resolver := getOperationResolver(operationDefinition, successCode, responseContent, options)
+			if options.Federation && operationType == types.Query {
+				plain := *operationDefinition
+				plain.ResultDefinition, plain.ResponseWrapperDefinition, plain.PaginationDefinition = nil, nil, nil
+				entityOperations = append(entityOperations, &entityOperation{
+					definition: &plain,
+					resolve:    getOperationResolver(&plain, successCode, responseContent, options),
+				})
+			}
 			field := &graphql.Field{
 				Name:        operationName,
 				Description: operation.Description,
 				Args:        args,
-				Type:        def.GraphQLType,
+				Type:        fieldType,
 				Resolve:     resolver,
 			}
 
 			if operationType == types.Query {
 				queryFields[operationName] = field
+				if utils.Contains(options.PollingSubscriptions, operationName) && !options.Mock {
+					subscriptionFields[operationName] = createPollingField(field, operationDefinition, poller, options)
+				}
 			} else {
 				mutationFields[operationName] = field
 			}
 
+			if len(operation.Callbacks) > 0 && len(options.WebhookURL) > 0 && options.Webhooks != nil && !options.Mock {
+				for name, subscriptionField := range createCallbackFields(builder, public, operation, operationDefinition, args, options) {
+					subscriptionFields[name] = subscriptionField
+				}
+			}
+
+			if len(operation.Tags) > 0 {
+				fieldTags[operationName] = operation.Tags[0]
+			}
+			operations = append(operations, operationDefinition)
+			reportOperation(options, path, method, operationName, types.OperationTranslated, note)
 			log.Print("Added field: " + operationName)
 		}
 		log.Print("Path processed: " + path)
 	}
 
+	if options.TagNamespaces {
+		queryFields = groupFieldsByTag(builder, public, queryFields, fieldTags, "Query")
+		mutationFields = groupFieldsByTag(builder, public, mutationFields, fieldTags, "Mutation")
+	}
+	var entities []*federation.Entity
+	if options.Federation {
+		entities = createEntities(entityOperations, options)
+		for name, field := range federation.CreateFields(entities) {
+			queryFields[name] = field
+		}
+	}
+	if records && options.Report != nil {
+		options.Report.AddCollisions(recorder.Collisions()[reported:]...)
+	}
+
+	return rootFields{query: queryFields, mutation: mutationFields, subscription: subscriptionFields, entities: entities}, operations, nil
+}
+
+// Returns schema config with root types of non-empty fields
+func newSchemaConfig(fields rootFields) graphql.SchemaConfig {
 	config := graphql.SchemaConfig{}
 
-	if len(mutationFields) > 0 {
+	if len(fields.mutation) > 0 {
 		config.Mutation = graphql.NewObject(graphql.ObjectConfig{
 			Name:   "Mutation",
-			Fields: mutationFields,
+			Fields: fields.mutation,
 		})
 	}
-	if len(queryFields) > 0 {
+	if len(fields.query) > 0 {
 		config.Query = graphql.NewObject(graphql.ObjectConfig{
 			Name:   "Query",
-			Fields: queryFields,
+			Fields: fields.query,
+		})
+	}
+	if len(fields.subscription) > 0 {
+		config.Subscription = graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Subscription",
+			Fields: fields.subscription,
 		})
 	}
 
 	return config
 }
+
+// Returns resolver calling upstream, or generated data in mock mode
+func getOperationResolver(operationDef *types.OperationDefinition, successCode string, responseContent types.ResponseContent, options types.Options) func(p graphql.ResolveParams) (interface{}, error) {
+	if options.Mock {
+		return GetMockResolver(operationDef, successCode, responseContent, options)
+	}
+	return GetResolver(getClient(options), operationDef, options)
+}
+
+// Returns union definition of success and error response types. Returns nil if operation has no error responses with object schema
+func createResultDefinition(builder *typebuilder.Builder, public *openapi3.T, operation *openapi3.Operation, operationName string, path string, successCode string, successDef *types.DataDefinition) *types.ResultDefinition {
+	if typebuilder.GetObjectType(successDef) == nil {
+		return nil
+	}
+
+	errors := make([]*types.ErrorResponseDefinition, 0)
+	for _, errorResponse := range GetErrorResponses(operation.Responses) {
+		if errorResponse.StatusCode == successCode {
+			continue
+		}
+		content, err := GetResponseContent(*operation.Responses[errorResponse.StatusCode])
+		if err != nil {
+			continue
+		}
+
+		fromPath := utils.InferResourceNameFromPath(path) + utils.ToPascalCase(errorResponse.StatusCode) + "Error"
+		if code, err := strconv.Atoi(errorResponse.StatusCode); err == nil {
+			fromPath = utils.InferResourceNameFromPath(path) + utils.ToPascalCase(http.StatusText(code))
+		}
+
+		schemaNames := types.SchemaNames{
+			FromSchema: content.Content.Schema.Value.Title,
+			FromRef:    utils.GetRefName(content.Content.Schema.Ref),
+			FromPath:   fromPath,
+		}
+		errorResponse.DataDefinition = builder.CreateDataDefinition(public, content.Content.Schema, schemaNames, path, false)
+
+		if typebuilder.GetObjectType(errorResponse.DataDefinition) != nil {
+			errors = append(errors, errorResponse)
+		}
+	}
+
+	if len(errors) == 0 {
+		return nil
+	}
+
+	return &types.ResultDefinition{
+		GraphQLTypeName: builder.PrefixTypeName(utils.ToPascalCase(operationName) + "Result"),
+		Success:         successDef,
+		Errors:          errors,
+	}
+}
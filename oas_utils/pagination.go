@@ -0,0 +1,369 @@
+package oas_utils
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/graphql-go/graphql"
+)
+
+const paginationExtension = "x-graphql-pagination"
+
+// Value of x-graphql-pagination extension. Parameter names are original OAS names. Set extension to false to disable pagination
+type paginationConfig struct {
+	Style            string `json:"style"`
+	LimitParam       string `json:"limitParam"`
+	OffsetParam      string `json:"offsetParam"`
+	PageParam        string `json:"pageParam"`
+	CursorParam      string `json:"cursorParam"`
+	PageSize         int    `json:"pageSize"`
+	ItemsField       string `json:"itemsField"`
+	HasNextPageField string `json:"hasNextPageField"`
+	NextCursorField  string `json:"nextCursorField"`
+	NextCursorHeader string `json:"nextCursorHeader"`
+	TotalCountField  string `json:"totalCountField"`
+	TotalCountHeader string `json:"totalCountHeader"`
+}
+
+var (
+	limitParamNames       = []string{"limit", "perpage", "pagesize", "size", "count", "maxresults"}
+	offsetParamNames      = []string{"offset", "skip", "start"}
+	pageParamNames        = []string{"page", "pagenumber", "pagenum"}
+	cursorParamNames      = []string{"cursor", "after", "pagetoken", "nexttoken", "continuationtoken", "startingafter"}
+	itemsFieldNames       = []string{"items", "data", "results", "records", "entries", "values"}
+	hasNextPageFieldNames = []string{"hasmore", "hasnextpage", "hasnext"}
+	nextCursorFieldNames  = []string{"nextcursor", "nextpagetoken", "nexttoken", "next"}
+	totalCountFieldNames  = []string{"totalcount", "total", "count"}
+)
+
+// Returns pagination definition of list operation or nil if operation can't be paginated
+func createPaginationDefinition(operation *openapi3.Operation, argToParam map[string]*openapi3.ParameterRef, responseDef *types.DataDefinition) *types.PaginationDefinition {
+	var disabled bool
+	if utils.GetExtension(operation.Extensions, paginationExtension, &disabled) && !disabled {
+		return nil
+	}
+	config := paginationConfig{}
+	utils.GetExtension(operation.Extensions, paginationExtension, &config)
+
+	pagination := &types.PaginationDefinition{
+		PageSize:         config.PageSize,
+		TotalCountHeader: "X-Total-Count",
+		NextCursorHeader: config.NextCursorHeader,
+	}
+	if len(config.TotalCountHeader) > 0 {
+		pagination.TotalCountHeader = config.TotalCountHeader
+	}
+
+	pagination.LimitArgName, _ = findPaginationArg(argToParam, config.LimitParam, limitParamNames)
+	pagination.OffsetArgName, _ = findPaginationArg(argToParam, config.OffsetParam, offsetParamNames)
+	pagination.PageArgName, _ = findPaginationArg(argToParam, config.PageParam, pageParamNames)
+	pagination.CursorArgName, pagination.CursorParamName = findPaginationArg(argToParam, config.CursorParam, cursorParamNames)
+
+	pagination.Style = config.Style
+	if len(pagination.Style) == 0 {
+		if len(pagination.CursorArgName) > 0 {
+			pagination.Style = types.CursorPagination
+		} else if len(pagination.OffsetArgName) > 0 {
+			pagination.Style = types.OffsetPagination
+		} else if len(pagination.PageArgName) > 0 {
+			pagination.Style = types.PagePagination
+		}
+	}
+	switch pagination.Style {
+	case types.CursorPagination:
+		if len(pagination.CursorArgName) == 0 {
+			return nil
+		}
+	case types.OffsetPagination:
+		if len(pagination.OffsetArgName) == 0 {
+			return nil
+		}
+	case types.PagePagination:
+		if len(pagination.PageArgName) == 0 {
+			return nil
+		}
+		if pagination.PageSize == 0 && len(pagination.LimitArgName) > 0 {
+			if schema := argToParam[pagination.LimitArgName].Value.Schema; schema != nil && schema.Value != nil {
+				pagination.PageSize = int(castToFloat(schema.Value.Default))
+			}
+		}
+	default:
+		return nil
+	}
+
+	if responseDef.TargetGraphQLType == types.List {
+		pagination.ItemDefinition = responseDef.ListItemDefinitions
+		return pagination
+	}
+	if responseDef.TargetGraphQLType != types.Object {
+		return nil
+	}
+
+	properties := responseDef.ObjectPropertiesDefinitions
+	pagination.ItemsField = findPaginationField(properties, config.ItemsField, itemsFieldNames)
+	itemsDef, ok := properties[pagination.ItemsField]
+	if !ok || itemsDef.TargetGraphQLType != types.List {
+		return nil
+	}
+	pagination.ItemDefinition = itemsDef.ListItemDefinitions
+	pagination.HasNextPageField = findPaginationField(properties, config.HasNextPageField, hasNextPageFieldNames)
+	pagination.NextCursorField = findPaginationField(properties, config.NextCursorField, nextCursorFieldNames)
+	pagination.TotalCountField = findPaginationField(properties, config.TotalCountField, totalCountFieldNames)
+
+	return pagination
+}
+
+// Returns argument and parameter names. Explicit parameter name has priority over known names, known names are tried in order
+func findPaginationArg(argToParam map[string]*openapi3.ParameterRef, explicit string, knownNames []string) (string, string) {
+	argNames := make([]string, 0, len(argToParam))
+	for argName := range argToParam {
+		argNames = append(argNames, argName)
+	}
+	sort.Strings(argNames)
+
+	if len(explicit) > 0 {
+		knownNames = []string{explicit}
+	}
+	for _, knownName := range knownNames {
+		for _, argName := range argNames {
+			param := argToParam[argName].Value
+			if param.In != "query" {
+				continue
+			}
+			if (len(explicit) > 0 && param.Name == explicit) || (len(explicit) == 0 && normalizePaginationName(param.Name) == knownName) {
+				return argName, param.Name
+			}
+		}
+	}
+	return "", ""
+}
+
+func findPaginationField(properties map[string]*types.DataDefinition, explicit string, knownNames []string) string {
+	if len(explicit) > 0 {
+		return explicit
+	}
+	fieldNames := make([]string, 0, len(properties))
+	for fieldName := range properties {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	for _, knownName := range knownNames {
+		for _, fieldName := range fieldNames {
+			if normalizePaginationName(fieldName) == knownName {
+				return fieldName
+			}
+		}
+	}
+	return ""
+}
+
+func normalizePaginationName(name string) string {
+	return strings.ToLower(utils.Sanitize(name))
+}
+
+// Returns connection field arguments. Upstream paging arguments are replaced by first and after
+func getConnectionArgs(args graphql.FieldConfigArgument, pagination *types.PaginationDefinition) graphql.FieldConfigArgument {
+	connectionArgs := graphql.FieldConfigArgument{}
+	for name, arg := range args {
+		if name != pagination.LimitArgName && name != pagination.OffsetArgName && name != pagination.PageArgName && name != pagination.CursorArgName {
+			connectionArgs[name] = arg
+		}
+	}
+	connectionArgs["first"] = &graphql.ArgumentConfig{Type: graphql.Int, Description: "Returns the first n elements"}
+	connectionArgs["after"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "Returns the elements that come after the specified cursor"}
+	return connectionArgs
+}
+
+// Translates first and after arguments to upstream paging arguments
+func getUpstreamPaginationArgs(args map[string]interface{}, pagination *types.PaginationDefinition) map[string]interface{} {
+	upstreamArgs := make(map[string]interface{})
+	for name, value := range args {
+		if name != "first" && name != "after" {
+			upstreamArgs[name] = value
+		}
+	}
+
+	first, hasFirst := args["first"].(int)
+	after, _ := args["after"].(string)
+	if hasFirst && len(pagination.LimitArgName) > 0 {
+		upstreamArgs[pagination.LimitArgName] = first
+	}
+
+	switch pagination.Style {
+	case types.CursorPagination:
+		if cursor, ok := decodeCursor(after, "cursor"); ok {
+			upstreamArgs[pagination.CursorArgName] = cursor
+		}
+	case types.OffsetPagination:
+		if offset, ok := decodeOffsetCursor(after); ok {
+			upstreamArgs[pagination.OffsetArgName] = offset
+		}
+	case types.PagePagination:
+		pageSize := pagination.PageSize
+		if hasFirst {
+			pageSize = first
+		}
+		offset, _ := decodeOffsetCursor(after)
+		if pageSize > 0 {
+			upstreamArgs[pagination.PageArgName] = offset/pageSize + 1
+		}
+	}
+
+	return upstreamArgs
+}
+
+// Returns connection with edges, pageInfo and totalCount built from upstream response
+func createConnection(args map[string]interface{}, data interface{}, header http.Header, pagination *types.PaginationDefinition) map[string]interface{} {
+	items, _ := data.([]interface{})
+	body, _ := data.(map[string]interface{})
+	if len(pagination.ItemsField) > 0 && body != nil {
+		items, _ = body[pagination.ItemsField].([]interface{})
+	}
+
+	after, _ := args["after"].(string)
+	offset, _ := decodeOffsetCursor(after)
+	nextCursor := getNextCursor(body, header, pagination)
+
+	edges := make([]interface{}, 0)
+	for i, item := range items {
+		cursor := interface{}(nil)
+		if pagination.Style != types.CursorPagination {
+			cursor = encodeCursor("offset", strconv.Itoa(offset+i+1))
+		} else if i == len(items)-1 && len(nextCursor) > 0 {
+			cursor = encodeCursor("cursor", nextCursor)
+		}
+		edges = append(edges, map[string]interface{}{
+			"node":   item,
+			"cursor": cursor,
+		})
+	}
+
+	totalCount := getTotalCount(body, header, pagination)
+	pageInfo := map[string]interface{}{
+		"hasNextPage":     hasNextPage(args, body, header, pagination, len(items), offset, totalCount, nextCursor),
+		"hasPreviousPage": len(after) > 0,
+		"startCursor":     nil,
+		"endCursor":       nil,
+	}
+	if len(edges) > 0 {
+		pageInfo["startCursor"] = edges[0].(map[string]interface{})["cursor"]
+		pageInfo["endCursor"] = edges[len(edges)-1].(map[string]interface{})["cursor"]
+	}
+
+	return map[string]interface{}{
+		"edges":      edges,
+		"pageInfo":   pageInfo,
+		"totalCount": totalCount,
+	}
+}
+
+// Order: body field, Link header, next cursor, total count, page is full
+func hasNextPage(args map[string]interface{}, body map[string]interface{}, header http.Header, pagination *types.PaginationDefinition, count int, offset int, totalCount interface{}, nextCursor string) bool {
+	if value, ok := body[pagination.HasNextPageField].(bool); ok && len(pagination.HasNextPageField) > 0 {
+		return value
+	}
+	if _, ok := parseLinkHeader(header.Get("Link"))["next"]; ok {
+		return true
+	}
+	if pagination.Style == types.CursorPagination {
+		return len(nextCursor) > 0
+	}
+	if total, ok := totalCount.(int); ok {
+		return offset+count < total
+	}
+	if first, ok := args["first"].(int); ok {
+		return count >= first && count > 0
+	}
+	return false
+}
+
+// Returns upstream cursor of the next page from body field, header or Link header
+func getNextCursor(body map[string]interface{}, header http.Header, pagination *types.PaginationDefinition) string {
+	if len(pagination.NextCursorField) > 0 {
+		if cursor := utils.CastToString(body[pagination.NextCursorField]); len(cursor) > 0 {
+			return cursor
+		}
+	}
+	if len(pagination.NextCursorHeader) > 0 {
+		if cursor := header.Get(pagination.NextCursorHeader); len(cursor) > 0 {
+			return cursor
+		}
+	}
+	if next, ok := parseLinkHeader(header.Get("Link"))["next"]; ok && len(pagination.CursorParamName) > 0 {
+		if u, err := url.Parse(next); err == nil {
+			return u.Query().Get(pagination.CursorParamName)
+		}
+	}
+	return ""
+}
+
+func getTotalCount(body map[string]interface{}, header http.Header, pagination *types.PaginationDefinition) interface{} {
+	if len(pagination.TotalCountField) > 0 {
+		if total, ok := body[pagination.TotalCountField].(float64); ok {
+			return int(total)
+		}
+	}
+	if total, err := strconv.Atoi(header.Get(pagination.TotalCountHeader)); err == nil {
+		return total
+	}
+	return nil
+}
+
+var linkRelRegexp = regexp.MustCompile(`rel="?([^";]+)"?`)
+
+// Returns URLs of RFC 8288 Link header by relation type
+func parseLinkHeader(link string) map[string]string {
+	links := make(map[string]string)
+	for _, part := range strings.Split(link, ",") {
+		sections := strings.SplitN(part, ";", 2)
+		if len(sections) < 2 {
+			continue
+		}
+		target := strings.Trim(strings.TrimSpace(sections[0]), "<>")
+		if match := linkRelRegexp.FindStringSubmatch(sections[1]); match != nil {
+			for _, rel := range strings.Fields(match[1]) {
+				links[rel] = target
+			}
+		}
+	}
+	return links
+}
+
+func encodeCursor(prefix string, value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(prefix + ":" + value))
+}
+
+func decodeCursor(cursor string, prefix string) (string, bool) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), prefix+":") {
+		return "", false
+	}
+	return strings.TrimPrefix(string(decoded), prefix+":"), true
+}
+
+func decodeOffsetCursor(cursor string) (int, bool) {
+	value, ok := decodeCursor(cursor, "offset")
+	if !ok {
+		return 0, false
+	}
+	offset, err := strconv.Atoi(value)
+	return offset, err == nil
+}
+
+func castToFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return 0
+}
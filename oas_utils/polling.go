@@ -0,0 +1,76 @@
+package oas_utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"openapi-to-graphql/subscriptions"
+	"openapi-to-graphql/types"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Returns subscription field which polls query operation and sends its result when upstream response changes
+func createPollingField(field *graphql.Field, operationDef *types.OperationDefinition, poller *subscriptions.Poller, options types.Options) *graphql.Field {
+	return &graphql.Field{
+		Name:        field.Name,
+		Description: field.Description,
+		Args:        field.Args,
+		Type:        field.Type,
+		Resolve:     subscriptions.Resolver(getPollingSubscribeFn(operationDef, poller, options)),
+	}
+}
+
+// Subscribers with identical arguments share one poll
+func getPollingSubscribeFn(operationDef *types.OperationDefinition, poller *subscriptions.Poller, options types.Options) subscriptions.SubscribeFn {
+	return func(ctx context.Context, args map[string]interface{}) (<-chan interface{}, error) {
+		// map keys are sorted by json encoder, so equal arguments give equal keys
+		key, err := json.Marshal(args)
+		if err != nil {
+			return nil, err
+		}
+		return poller.Subscribe(ctx, operationDef.OperationName+string(key), getPollFn(operationDef, args, options)), nil
+	}
+}
+
+// Compares responses by ETag if upstream returns it, otherwise by hash of response body
+func getPollFn(operationDef *types.OperationDefinition, args map[string]interface{}, options types.Options) subscriptions.PollFn {
+	return func(ctx context.Context, version string) (interface{}, string, bool, error) {
+		p := graphql.ResolveParams{Args: args, Context: ctx}
+
+		header := http.Header{}
+		if isETag(version) {
+			header.Set("If-None-Match", version)
+		}
+		response, responseBody, err := doRequest(getClient(options), p, operationDef, header)
+		if err != nil {
+			return nil, version, false, err
+		}
+		if response.StatusCode == http.StatusNotModified {
+			return nil, version, false, nil
+		}
+
+		newVersion := response.Header.Get("ETag")
+		if len(newVersion) == 0 {
+			hash := sha256.Sum256(responseBody)
+			newVersion = hex.EncodeToString(hash[:])
+		}
+		if newVersion == version {
+			return nil, version, false, nil
+		}
+
+		value, err := resolveResponse(p, operationDef, options, response, responseBody)
+		if err != nil {
+			return nil, version, false, err
+		}
+		return value, newVersion, true, nil
+	}
+}
+
+// ETags are quoted, body hashes are not
+func isETag(version string) bool {
+	return strings.HasSuffix(version, `"`)
+}
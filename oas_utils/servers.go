@@ -0,0 +1,79 @@
+package oas_utils
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"openapi-to-graphql/types"
+	"openapi-to-graphql/utils"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var serverVariable = regexp.MustCompile(`\{[^}]*\}`)
+
+// Returns server URL of spec. options.ServerURL overrides servers of spec, options.ServerIndex selects one of them
+func GetServerUrl(public *openapi3.T, options types.Options) (string, error) {
+	if len(options.ServerURL) > 0 {
+		return strings.TrimSuffix(options.ServerURL, "/"), nil
+	}
+	if len(public.Servers) == 0 {
+		return "", errors.New("Server URL not found")
+	}
+	if options.ServerIndex < 0 || options.ServerIndex >= len(public.Servers) {
+		return "", fmt.Errorf("Server %d not found, spec has %d servers", options.ServerIndex, len(public.Servers))
+	}
+	return resolveServerUrl(public.Servers[options.ServerIndex], options)
+}
+
+// Returns server URL of operation, the first server of operation or path. Empty URL is returned if server of spec is used,
+// i.e. operation and path have no servers or options.ServerURL overrides them
+func getOperationServerUrl(pathItem *openapi3.PathItem, operation *openapi3.Operation, options types.Options) (string, error) {
+	if len(options.ServerURL) == 0 {
+		if operation.Servers != nil && len(*operation.Servers) > 0 {
+			return resolveServerUrl((*operation.Servers)[0], options)
+		}
+		if len(pathItem.Servers) > 0 {
+			return resolveServerUrl(pathItem.Servers[0], options)
+		}
+	}
+	return "", nil
+}
+
+// Returns URL of server with variables replaced by options.ServerVariables or defaults.
+// Relative URL is resolved against options.SpecURL, e.g. https://example.com/v1 of /v1
+func resolveServerUrl(server *openapi3.Server, options types.Options) (string, error) {
+	serverUrl := server.URL
+	for name, variable := range server.Variables {
+		value := variable.Default
+		if override, ok := options.ServerVariables[name]; ok {
+			value = override
+		}
+		if len(variable.Enum) > 0 && !utils.Contains(variable.Enum, value) {
+			return "", fmt.Errorf("Value %s of server variable %s is not one of %s", value, name, strings.Join(variable.Enum, ", "))
+		}
+		serverUrl = strings.ReplaceAll(serverUrl, "{"+name+"}", value)
+	}
+	if undefined := serverVariable.FindString(serverUrl); len(undefined) > 0 {
+		return "", fmt.Errorf("Server variable %s of %s is not defined", undefined, server.URL)
+	}
+
+	parsed, err := url.Parse(serverUrl)
+	if err != nil {
+		return "", err
+	}
+	if !parsed.IsAbs() {
+		if len(options.SpecURL) == 0 {
+			return "", fmt.Errorf("Server URL %s is relative, spec URL or server URL is required", server.URL)
+		}
+		base, err := url.Parse(options.SpecURL)
+		if err != nil {
+			return "", err
+		}
+		serverUrl = base.ResolveReference(parsed).String()
+	}
+	return strings.TrimSuffix(serverUrl, "/"), nil
+}
@@ -0,0 +1,56 @@
+package oas_utils
+
+import (
+	"context"
+	"net/http"
+	"openapi-to-graphql/tracing"
+	"openapi-to-graphql/types"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Starts span of operation with operationId, HTTP method and URL template attributes. Operation without operationId
+// has no operationId attribute
+func startOperationSpan(ctx context.Context, name string, kind trace.SpanKind, operationDef *types.OperationDefinition) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	attributes := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(strings.ToUpper(operationDef.HttpMethod)),
+		attribute.String(tracing.URLTemplateKey, operationDef.ServerUrl+operationDef.Path),
+	}
+	if len(operationDef.OperationID) > 0 {
+		attributes = append(attributes, attribute.String(tracing.OperationIdKey, operationDef.OperationID))
+	}
+	return tracing.Tracer().Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attributes...))
+}
+
+// Adds W3C trace context of span in ctx to upstream request
+func injectTraceContext(ctx context.Context, request *http.Request) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+}
+
+// Sets status code and response size of upstream response on client span
+func endClientSpan(span trace.Span, response *http.Response, responseBody []byte, err error) {
+	defer span.End()
+	if err != nil {
+		recordError(span, err)
+		return
+	}
+	span.SetAttributes(
+		semconv.HTTPStatusCodeKey.Int(response.StatusCode),
+		attribute.Int("http.response_size", len(responseBody)),
+	)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(response.StatusCode, trace.SpanKindClient))
+}
+
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
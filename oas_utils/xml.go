@@ -0,0 +1,215 @@
+package oas_utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+type xmlNode struct {
+	Name     string
+	Attrs    map[string]string
+	Children []*xmlNode
+	Text     string
+}
+
+// OAS xml object
+type XMLObject struct {
+	Name      string
+	Attribute bool
+	Wrapped   bool
+}
+
+// Decodes XML document to the same representation as json.Unmarshal does, using schema xml objects (name, attribute, wrapped)
+func DecodeXML(data []byte, schema *openapi3.Schema) (interface{}, error) {
+	root, err := parseXMLTree(data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeXMLNode(root, schema), nil
+}
+
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlNode
+	var root *xmlNode
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{Name: t.Name.Local, Attrs: make(map[string]string)}
+			for _, attr := range t.Attr {
+				node.Attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("xml root element not found")
+	}
+	return root, nil
+}
+
+func decodeXMLNode(node *xmlNode, schema *openapi3.Schema) interface{} {
+	if schema == nil {
+		return strings.TrimSpace(node.Text)
+	}
+
+	switch {
+	case schema.Type == "array":
+		// root array is always wrapped, otherwise document has no single root element
+		return decodeXMLItems(node.Children, "", schema)
+	case schema.Type == "object" || len(schema.Properties) > 0 || len(schema.AllOf) > 0:
+		return decodeXMLObject(node, schema)
+	default:
+		return decodeScalar(node.Text, schema)
+	}
+}
+
+func decodeXMLObject(node *xmlNode, schema *openapi3.Schema) map[string]interface{} {
+	object := make(map[string]interface{})
+
+	for propertyName, property := range getObjectProperties(schema) {
+		if property.Value == nil {
+			continue
+		}
+		xmlObj := GetXMLObject(property.Value)
+		name := propertyName
+		if len(xmlObj.Name) > 0 {
+			name = xmlObj.Name
+		}
+
+		if xmlObj.Attribute {
+			if value, ok := node.Attrs[name]; ok {
+				object[propertyName] = decodeScalar(value, property.Value)
+			}
+			continue
+		}
+
+		if property.Value.Type == "array" {
+			children := node.Children
+			if xmlObj.Wrapped {
+				wrapper := findXMLChild(node, name)
+				if wrapper == nil {
+					continue
+				}
+				children = wrapper.Children
+			}
+			object[propertyName] = decodeXMLItems(children, name, property.Value)
+			continue
+		}
+
+		if child := findXMLChild(node, name); child != nil {
+			object[propertyName] = decodeXMLNode(child, property.Value)
+		}
+	}
+
+	return object
+}
+
+// Decodes array items. Item element name is taken from items xml object, then from array property name
+func decodeXMLItems(children []*xmlNode, name string, schema *openapi3.Schema) []interface{} {
+	items := make([]interface{}, 0)
+	var itemSchema *openapi3.Schema
+	if schema.Items != nil {
+		itemSchema = schema.Items.Value
+	}
+
+	itemName := name
+	if itemSchema != nil {
+		if xmlObj := GetXMLObject(itemSchema); len(xmlObj.Name) > 0 {
+			itemName = xmlObj.Name
+		}
+	}
+
+	for _, child := range children {
+		if len(itemName) == 0 || child.Name == itemName {
+			items = append(items, decodeXMLNode(child, itemSchema))
+		}
+	}
+	return items
+}
+
+func decodeScalar(text string, schema *openapi3.Schema) interface{} {
+	text = strings.TrimSpace(text)
+
+	switch schema.Type {
+	case "integer":
+		if v, err := strconv.Atoi(text); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(text, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(text); err == nil {
+			return v
+		}
+	}
+	return text
+}
+
+func findXMLChild(node *xmlNode, name string) *xmlNode {
+	for _, child := range node.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// Returns properties of schema and its allOf schemas
+func getObjectProperties(schema *openapi3.Schema) openapi3.Schemas {
+	properties := make(openapi3.Schemas)
+	for _, allOfSchema := range schema.AllOf {
+		if allOfSchema.Value != nil {
+			for name, property := range getObjectProperties(allOfSchema.Value) {
+				properties[name] = property
+			}
+		}
+	}
+	for name, property := range schema.Properties {
+		properties[name] = property
+	}
+	return properties
+}
+
+// Returns xml object of schema: element name, attribute and wrapped array
+func GetXMLObject(schema *openapi3.Schema) XMLObject {
+	xmlObj := XMLObject{}
+	values, ok := schema.XML.(map[string]interface{})
+	if !ok {
+		return xmlObj
+	}
+	xmlObj.Name, _ = values["name"].(string)
+	xmlObj.Attribute, _ = values["attribute"].(bool)
+	xmlObj.Wrapped, _ = values["wrapped"].(bool)
+	return xmlObj
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"openapi-to-graphql/subscriptions"
+	"openapi-to-graphql/utils"
+)
+
+// Set on shutdown, /readyz fails while in-flight requests are drained
+var draining int32
+
+// Serves until SIGTERM or interrupt. Then /readyz fails for grace period, so load balancer stops sending requests,
+// and server stops accepting connections and waits for in-flight requests until timeout. Another signal skips grace period.
+// Open subscriptions are completed on shutdown. TLS is served if certificate file is set
+func serve(server *http.Server, certFile string, keyFile string, grace time.Duration, timeout time.Duration) error {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(stop)
+	server.RegisterOnShutdown(subscriptions.Shutdown)
+
+	errs := make(chan error, 1)
+	go func() {
+		if len(certFile) > 0 {
+			errs <- server.ListenAndServeTLS(certFile, keyFile)
+		} else {
+			errs <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case sig := <-stop:
+		log.Print("Draining on " + sig.String() + ", shutting down in " + grace.String())
+	}
+
+	atomic.StoreInt32(&draining, 1)
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+	case <-time.After(grace):
+	}
+
+	log.Print("Shutting down, waiting for in-flight requests")
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
+// Allows cross-origin requests of origins, * allows every origin. Websocket upgrade of other origins is forbidden
+func withCORS(next http.Handler, origins []string, headers string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if len(origin) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !utils.Contains(origins, "*") && !utils.Contains(origins, origin) {
+			if subscriptions.IsWebsocketRequest(r) {
+				http.Error(w, "Origin "+origin+" is not allowed", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if r.Method == http.MethodOptions && len(r.Header.Get("Access-Control-Request-Method")) > 0 {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Rejects request bodies larger than limit with 413, body of unknown length is cut at limit
+func withBodyLimit(next http.Handler, limit int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > limit {
+			http.Error(w, "Request body is larger than "+strconv.FormatInt(limit, 10)+" bytes", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Liveness of server, always ok while server accepts requests
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+}
+
+// Returns readiness handler. Server is not ready while shutting down or if an upstream can't be reached within timeout.
+// Upstream responding with status below 500 is ready, no upstream is probed if upstreams are empty
+func newReadyHandler(upstreams []string, client *http.Client, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&draining) == 1 {
+			http.Error(w, "Shutting down", http.StatusServiceUnavailable)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		errs := make([]string, 0)
+		for _, upstream := range upstreams {
+			if err := probe(ctx, client, upstream); err != nil {
+				errs = append(errs, upstream+": "+err.Error())
+			}
+		}
+		if len(errs) > 0 {
+			http.Error(w, "Upstreams are not ready:\n"+strings.Join(errs, "\n"), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+}
+
+func probe(ctx context.Context, client *http.Client, upstream string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, upstream, nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode >= 500 {
+		return fmt.Errorf("status %s", response.Status)
+	}
+	return nil
+}
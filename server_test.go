@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"openapi-to-graphql/subscriptions"
+
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
+)
+
+func TestCORS(t *testing.T) {
+	h := withCORS(http.HandlerFunc(healthHandler), []string{"https://app.example.com"}, "Content-Type, Authorization")
+
+	// preflight of allowed origin
+	request := httptest.NewRequest(http.MethodOptions, "/", nil)
+	request.Header.Set("Origin", "https://app.example.com")
+	request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("got status %d of preflight, want %d", recorder.Code, http.StatusNoContent)
+	}
+	for name, want := range map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
+		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+		"Vary":                         "Origin",
+	} {
+		if got := recorder.Header().Get(name); got != want {
+			t.Errorf("got %s %q, want %q", name, got, want)
+		}
+	}
+
+	// websocket upgrade of disallowed origin
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Origin", "https://evil.example.com")
+	request.Header.Set("Connection", "Upgrade")
+	request.Header.Set("Upgrade", "websocket")
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("got status %d of websocket upgrade, want %d", recorder.Code, http.StatusForbidden)
+	}
+
+	// request of disallowed origin is served without CORS headers, browser rejects response
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Origin", "https://evil.example.com")
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || len(recorder.Header().Get("Access-Control-Allow-Origin")) > 0 {
+		t.Errorf("got status %d and allowed origin %q", recorder.Code, recorder.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	h := withBodyLimit(http.HandlerFunc(healthHandler), 10)
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"query":"{ a }"}`)))
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d of large body, want %d", recorder.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("got status %d of small body, want %d", recorder.Code, http.StatusOK)
+	}
+}
+
+func TestReady(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(healthHandler))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	unreachable := httptest.NewServer(http.HandlerFunc(healthHandler))
+	unreachable.Close()
+
+	for _, c := range []struct {
+		name      string
+		upstreams []string
+		want      int
+	}{
+		{"without upstreams", nil, http.StatusOK},
+		{"healthy upstream", []string{healthy.URL}, http.StatusOK},
+		{"upstream responding 5xx", []string{healthy.URL, failing.URL}, http.StatusServiceUnavailable},
+		{"unreachable upstream", []string{unreachable.URL}, http.StatusServiceUnavailable},
+	} {
+		recorder := httptest.NewRecorder()
+		newReadyHandler(c.upstreams, &http.Client{}, time.Second).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		if recorder.Code != c.want {
+			t.Errorf("%s: got status %d, want %d", c.name, recorder.Code, c.want)
+		}
+	}
+
+	atomic.StoreInt32(&draining, 1)
+	defer atomic.StoreInt32(&draining, 0)
+	recorder := httptest.NewRecorder()
+	newReadyHandler([]string{healthy.URL}, &http.Client{}, time.Second).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("got status %d while draining, want %d", recorder.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestWebsocketOrigin(t *testing.T) {
+	schema := newSubscriptionSchema(t)
+	gateway := httptest.NewServer(subscriptions.NewWebsocketHandler(&schema, nil))
+	defer gateway.Close()
+	allowing := httptest.NewServer(subscriptions.NewWebsocketHandler(&schema, []string{"https://app.example.com"}))
+	defer allowing.Close()
+
+	for _, c := range []struct {
+		name   string
+		server *httptest.Server
+		origin string
+		want   int
+	}{
+		{"without origin", gateway, "", http.StatusSwitchingProtocols},
+		{"same origin", gateway, gateway.URL, http.StatusSwitchingProtocols},
+		{"other origin", gateway, "https://evil.example.com", http.StatusForbidden},
+		{"allowed origin", allowing, "https://app.example.com", http.StatusSwitchingProtocols},
+		{"origin which is not allowed", allowing, "https://evil.example.com", http.StatusForbidden},
+	} {
+		header := http.Header{}
+		if len(c.origin) > 0 {
+			header.Set("Origin", c.origin)
+		}
+		conn, response, _ := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(c.server.URL, "http"), header)
+		if conn != nil {
+			conn.Close()
+		}
+		if response == nil || response.StatusCode != c.want {
+			t.Errorf("%s: got response %v, want status %d", c.name, response, c.want)
+		}
+	}
+}
+
+// Readiness fails during grace period, then open subscriptions are completed and server stops long before timeout
+func TestServeShutdown(t *testing.T) {
+	defer atomic.StoreInt32(&draining, 0)
+
+	schema := newSubscriptionSchema(t)
+	ws := subscriptions.NewWebsocketHandler(&schema, nil)
+	sse := subscriptions.NewSSEHandler(&schema)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", healthHandler)
+	mux.Handle("/readyz", newReadyHandler(nil, &http.Client{}, time.Second))
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		if subscriptions.IsWebsocketRequest(r) {
+			ws.ServeHTTP(w, r)
+			return
+		}
+		sse.ServeHTTP(w, r)
+	})
+
+	server := &http.Server{Addr: freeAddress(t), Handler: mux}
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- serve(server, "", "", 500*time.Millisecond, 10*time.Second)
+	}()
+	base := "http://" + server.Addr
+	waitForStatus(t, base+"/healthz", http.StatusOK)
+
+	request, _ := http.NewRequest(http.MethodGet, base+"/graphql?query="+url.QueryEscape("subscription { ticks }"), nil)
+	request.Header.Set("Accept", "text/event-stream")
+	// client timeout fails stream which is not completed
+	stream, err := (&http.Client{Timeout: 5 * time.Second}).Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+server.Addr+"/graphql", http.Header{"Sec-WebSocket-Protocol": []string{"graphql-transport-ws"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.WriteJSON(map[string]interface{}{"type": "connection_init"})
+	conn.WriteJSON(map[string]interface{}{"id": "1", "type": "subscribe", "payload": map[string]string{"query": "subscription { ticks }"}})
+	var ack map[string]interface{}
+	if err := conn.ReadJSON(&ack); err != nil || ack["type"] != "connection_ack" {
+		t.Fatalf("got %v %v, want connection_ack", ack, err)
+	}
+	// subscription is started before shutdown
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	syscall.Kill(os.Getpid(), syscall.SIGTERM)
+	waitForStatus(t, base+"/readyz", http.StatusServiceUnavailable)
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var complete map[string]interface{}
+	if err := conn.ReadJSON(&complete); err != nil || complete["type"] != "complete" || complete["id"] != "1" {
+		t.Errorf("got %v %v, want complete message of subscription", complete, err)
+	}
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("got %v, want going away close", err)
+	}
+
+	events := make([]string, 0)
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "event: ") {
+			events = append(events, strings.TrimPrefix(scanner.Text(), "event: "))
+		}
+	}
+	if strings.Join(events, ",") != "complete" {
+		t.Errorf("got events %v, want complete", events)
+	}
+
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("server stopped after %s, before grace period", elapsed)
+	}
+}
+
+// Returns schema with subscription which never sends events
+func newSubscriptionSchema(t *testing.T) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: graphql.Fields{"hello": &graphql.Field{Type: graphql.String}},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"ticks": &graphql.Field{
+					Type: graphql.String,
+					Resolve: subscriptions.Resolver(func(ctx context.Context, args map[string]interface{}) (<-chan interface{}, error) {
+						events := make(chan interface{})
+						go func() {
+							<-ctx.Done()
+							close(events)
+						}()
+						return events, nil
+					}),
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func waitForStatus(t *testing.T, url string, status int) {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		response, err := http.Get(url)
+		if err != nil {
+			continue
+		}
+		response.Body.Close()
+		if response.StatusCode == status {
+			return
+		}
+	}
+	t.Fatalf("%s did not respond %d", url, status)
+}
@@ -0,0 +1,190 @@
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"openapi-to-graphql/utils"
+
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
+)
+
+// Subprotocols of graphql-ws (graphql-transport-ws) and legacy subscriptions-transport-ws (graphql-ws)
+const (
+	graphqlTransportWS = "graphql-transport-ws"
+	graphqlWS          = "graphql-ws"
+)
+
+type message struct {
+	Id      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type wsHandler struct {
+	schema   *graphql.Schema
+	upgrader websocket.Upgrader
+}
+
+// Returns websocket handler of graphql-transport-ws and legacy graphql-ws protocols. Connections of the same origin
+// and of origins are accepted, * accepts every origin. Other origins are rejected, so websites visited by user
+// can't open websocket authenticated by user's cookies
+func NewWebsocketHandler(schema *graphql.Schema, origins []string) http.Handler {
+	return &wsHandler{
+		schema: schema,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{graphqlTransportWS, graphqlWS},
+			CheckOrigin: func(r *http.Request) bool {
+				return isAllowedOrigin(r, origins)
+			},
+		},
+	}
+}
+
+// Returns true if request has no origin, e.g. of non-browser client, or origin is host of request or one of origins
+func isAllowedOrigin(r *http.Request, origins []string) bool {
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 || utils.Contains(origins, "*") || utils.Contains(origins, origin) {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// Checks if request is websocket upgrade, used to serve GraphQL over HTTP and websocket on the same path
+func IsWebsocketRequest(r *http.Request) bool {
+	return websocket.IsWebSocketUpgrade(r)
+}
+
+type wsConnection struct {
+	conn     *websocket.Conn
+	legacy   bool
+	writeMu  sync.Mutex
+	cancelMu sync.Mutex
+	cancels  map[string]context.CancelFunc
+}
+
+func (h *wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Print("Websocket upgrade failed. " + err.Error())
+		return
+	}
+	defer conn.Close()
+
+	c := &wsConnection{
+		conn:    conn,
+		legacy:  conn.Subprotocol() == graphqlWS,
+		cancels: make(map[string]context.CancelFunc),
+	}
+	defer c.stopAll()
+	defer openStreams.add(c.shutdown)()
+
+	for {
+		var msg message
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case "connection_init":
+			c.write(message{Type: "connection_ack"})
+		case "ping":
+			c.write(message{Type: "pong"})
+		case "subscribe", "start":
+			var request Request
+			if err := json.Unmarshal(msg.Payload, &request); err != nil {
+				c.writeError(msg.Id, err)
+				continue
+			}
+			c.start(r.Context(), *h.schema, msg.Id, request)
+		case "complete", "stop":
+			c.stop(msg.Id)
+		case "connection_terminate":
+			return
+		}
+	}
+}
+
+func (c *wsConnection) start(parent context.Context, schema graphql.Schema, id string, request Request) {
+	ctx, cancel := context.WithCancel(parent)
+	c.cancelMu.Lock()
+	if previous, ok := c.cancels[id]; ok {
+		previous()
+	}
+	c.cancels[id] = cancel
+	c.cancelMu.Unlock()
+
+	dataType := "next"
+	if c.legacy {
+		dataType = "data"
+	}
+
+	go func() {
+		Execute(ctx, schema, request, func(result *graphql.Result) {
+			payload, err := json.Marshal(result)
+			if err != nil {
+				c.writeError(id, err)
+				return
+			}
+			c.write(message{Id: id, Type: dataType, Payload: payload})
+		})
+		if ctx.Err() == nil {
+			c.write(message{Id: id, Type: "complete"})
+		}
+		c.stop(id)
+	}()
+}
+
+func (c *wsConnection) stop(id string) {
+	c.cancelMu.Lock()
+	defer c.cancelMu.Unlock()
+	if cancel, ok := c.cancels[id]; ok {
+		cancel()
+		delete(c.cancels, id)
+	}
+}
+
+func (c *wsConnection) stopAll() {
+	c.cancelMu.Lock()
+	defer c.cancelMu.Unlock()
+	for id, cancel := range c.cancels {
+		cancel()
+		delete(c.cancels, id)
+	}
+}
+
+// Completes subscriptions and closes connection with going away status, read loop of handler ends with closed connection
+func (c *wsConnection) shutdown() {
+	c.cancelMu.Lock()
+	for id, cancel := range c.cancels {
+		cancel()
+		delete(c.cancels, id)
+		c.write(message{Id: id, Type: "complete"})
+	}
+	c.cancelMu.Unlock()
+
+	c.writeMu.Lock()
+	c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "Server is shutting down"))
+	c.writeMu.Unlock()
+	c.conn.Close()
+}
+
+func (c *wsConnection) writeError(id string, err error) {
+	payload, _ := json.Marshal([]map[string]string{{"message": err.Error()}})
+	c.write(message{Id: id, Type: "error", Payload: payload})
+}
+
+func (c *wsConnection) write(msg message) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := c.conn.WriteJSON(msg); err != nil {
+		log.Print("Websocket write failed. " + err.Error())
+	}
+}
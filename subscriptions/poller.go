@@ -0,0 +1,116 @@
+package subscriptions
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Polls upstream. Returns changed=false if value is the same as the one of previous version
+type PollFn func(ctx context.Context, version string) (value interface{}, newVersion string, changed bool, err error)
+
+// Polls upstream at interval while poll has subscribers. Subscribers of the same key share one poll
+type Poller struct {
+	interval time.Duration
+	mu       sync.Mutex
+	polls    map[string]*poll
+}
+
+type poll struct {
+	subscribers map[chan interface{}]struct{}
+	value       interface{}
+	hasValue    bool
+	cancel      context.CancelFunc
+}
+
+// Default interval is used if interval is not positive
+const DefaultPollInterval = 10 * time.Second
+
+func NewPoller(interval time.Duration) *Poller {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &Poller{
+		interval: interval,
+		polls:    make(map[string]*poll),
+	}
+}
+
+// Returns channel of changed values. Current value is sent right away if poll of the key is already running.
+// Poll errors are sent as error values
+func (pl *Poller) Subscribe(ctx context.Context, key string, fn PollFn) <-chan interface{} {
+	events := make(chan interface{}, 16)
+
+	pl.mu.Lock()
+	p, ok := pl.polls[key]
+	if !ok {
+		pollCtx, cancel := context.WithCancel(context.Background())
+		p = &poll{subscribers: make(map[chan interface{}]struct{}), cancel: cancel}
+		pl.polls[key] = p
+		go pl.run(pollCtx, key, p, fn)
+	} else if p.hasValue {
+		events <- p.value
+	}
+	p.subscribers[events] = struct{}{}
+	pl.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		pl.mu.Lock()
+		delete(p.subscribers, events)
+		if len(p.subscribers) == 0 {
+			p.cancel()
+			if pl.polls[key] == p {
+				delete(pl.polls, key)
+			}
+		}
+		pl.mu.Unlock()
+		close(events)
+	}()
+
+	return events
+}
+
+func (pl *Poller) run(ctx context.Context, key string, p *poll, fn PollFn) {
+	ticker := time.NewTicker(pl.interval)
+	defer ticker.Stop()
+
+	version := ""
+	for {
+		value, newVersion, changed, err := fn(ctx, version)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Print("Poll " + key + " failed. " + err.Error())
+			pl.publish(p, err)
+		} else if changed {
+			version = newVersion
+			pl.publish(p, value)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Slow subscribers miss values instead of blocking poll
+func (pl *Poller) publish(p *poll, value interface{}) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	if _, isError := value.(error); !isError {
+		p.value = value
+		p.hasValue = true
+	}
+	for events := range p.subscribers {
+		select {
+		case events <- value:
+		default:
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package subscriptions
+
+import (
+	"context"
+	"sync"
+)
+
+// Delivers published payloads to subscribers of a topic
+type PubSub struct {
+	mu     sync.RWMutex
+	topics map[string]map[chan interface{}]struct{}
+}
+
+func NewPubSub() *PubSub {
+	return &PubSub{topics: make(map[string]map[chan interface{}]struct{})}
+}
+
+// Returns channel of topic payloads. Subscriber is removed and channel is closed when context is done
+func (ps *PubSub) Subscribe(ctx context.Context, topic string) <-chan interface{} {
+	events := make(chan interface{}, 16)
+
+	ps.mu.Lock()
+	if ps.topics[topic] == nil {
+		ps.topics[topic] = make(map[chan interface{}]struct{})
+	}
+	ps.topics[topic][events] = struct{}{}
+	ps.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		ps.mu.Lock()
+		delete(ps.topics[topic], events)
+		if len(ps.topics[topic]) == 0 {
+			delete(ps.topics, topic)
+		}
+		ps.mu.Unlock()
+		close(events)
+	}()
+
+	return events
+}
+
+// Returns false if topic has no subscribers. Slow subscribers miss payloads instead of blocking publisher
+func (ps *PubSub) Publish(topic string, payload interface{}) bool {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	subscribers := ps.topics[topic]
+	for events := range subscribers {
+		select {
+		case events <- payload:
+		default:
+		}
+	}
+	return len(subscribers) > 0
+}
+
+// Returns number of topic subscribers
+func (ps *PubSub) Subscribers(topic string) int {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return len(ps.topics[topic])
+}
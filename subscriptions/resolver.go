@@ -0,0 +1,83 @@
+package subscriptions
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Starts subscription with field arguments. Returned channel should be closed when context is done
+type SubscribeFn func(ctx context.Context, args map[string]interface{}) (<-chan interface{}, error)
+
+type subscribeContextKey struct{}
+
+// Source of subscription events found while resolving subscription operation
+type subscription struct {
+	fieldName string
+	events    <-chan interface{}
+}
+
+// Returns resolver of subscription root field.
+// While subscribing it starts subscription, for every event it resolves event payload from root value
+func Resolver(subscribe SubscribeFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if s, ok := p.Context.Value(subscribeContextKey{}).(*subscription); ok {
+			events, err := subscribe(p.Context, p.Args)
+			if err != nil {
+				return nil, err
+			}
+			s.fieldName = p.Info.FieldName
+			s.events = events
+			return nil, nil
+		}
+
+		root, _ := p.Info.RootValue.(map[string]interface{})
+		if err, ok := root[p.Info.FieldName].(error); ok {
+			return nil, err
+		}
+		return root[p.Info.FieldName], nil
+	}
+}
+
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Executes request and calls send with result of every event. Queries and mutations are executed once.
+// Blocks until events are over or context is done
+func Execute(ctx context.Context, schema graphql.Schema, request Request, send func(*graphql.Result)) {
+	s := &subscription{}
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  request.Query,
+		VariableValues: request.Variables,
+		OperationName:  request.OperationName,
+		Context:        context.WithValue(ctx, subscribeContextKey{}, s),
+	})
+
+	if len(result.Errors) > 0 || s.events == nil {
+		send(result)
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-s.events:
+			if !ok {
+				return
+			}
+			send(graphql.Do(graphql.Params{
+				Schema:         schema,
+				RequestString:  request.Query,
+				VariableValues: request.Variables,
+				OperationName:  request.OperationName,
+				RootObject:     map[string]interface{}{s.fieldName: event},
+				Context:        ctx,
+			}))
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package subscriptions
+
+import "sync"
+
+// Open websocket connections and SSE streams of all handlers, handlers of reloaded schemas included
+var openStreams = &streams{closers: make(map[int]func())}
+
+type streams struct {
+	mu      sync.Mutex
+	next    int
+	closers map[int]func()
+}
+
+// Adds close function of open stream. Returned function removes it, it is called when stream ends
+func (s *streams) add(close func()) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.next
+	s.next++
+	s.closers[id] = close
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.closers, id)
+	}
+}
+
+// Completes open subscriptions of all websocket and SSE handlers. Websocket subscriptions get complete message and
+// connection is closed with going away status, SSE streams get complete event. It is http.Server.RegisterOnShutdown hook:
+// Shutdown doesn't close hijacked websocket connections and waits for SSE streams until its context is done
+func Shutdown() {
+	openStreams.mu.Lock()
+	closers := make([]func(), 0, len(openStreams.closers))
+	for _, close := range openStreams.closers {
+		closers = append(closers, close)
+	}
+	openStreams.mu.Unlock()
+
+	for _, close := range closers {
+		close()
+	}
+}
@@ -0,0 +1,76 @@
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+type sseHandler struct {
+	schema *graphql.Schema
+}
+
+// Returns Server-Sent Events handler. Every result is sent as next event, then complete event is sent.
+// Request is read from query (GET) or JSON body (POST)
+func NewSSEHandler(schema *graphql.Schema) http.Handler {
+	return &sseHandler{schema: schema}
+}
+
+// Checks if client accepts event stream, used to serve GraphQL over HTTP and SSE on the same path
+func IsSSERequest(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+func (h *sseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var request Request
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		query := r.URL.Query()
+		request.Query = query.Get("query")
+		request.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); len(variables) > 0 {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// stream is completed on shutdown
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	defer openStreams.add(cancel)()
+
+	Execute(ctx, *h.schema, request, func(result *graphql.Result) {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return
+		}
+		w.Write([]byte("event: next\ndata: " + string(data) + "\n\n"))
+		flusher.Flush()
+	})
+
+	if r.Context().Err() == nil {
+		w.Write([]byte("event: complete\ndata:\n\n"))
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,47 @@
+package subscriptions
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+type webhookHandler struct {
+	pubsub *PubSub
+	prefix string
+}
+
+// Returns receiver of upstream callback requests. Request to {prefix}/{topic} is published to topic subscribers
+func NewWebhookHandler(pubsub *PubSub, prefix string) http.Handler {
+	return &webhookHandler{
+		pubsub: pubsub,
+		prefix: strings.TrimSuffix(prefix, "/") + "/",
+	}
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	topic := strings.TrimPrefix(r.URL.Path, h.prefix)
+	if len(topic) == 0 || r.Method == http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		payload = string(body)
+	}
+
+	// 410 tells upstream that nobody listens to callbacks anymore
+	if !h.pubsub.Publish(topic, payload) {
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
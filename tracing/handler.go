@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type handler struct {
+	next http.Handler
+}
+
+// Returns handler which starts server span of every request. Incoming trace context is continued
+func NewHandler(next http.Handler) http.Handler {
+	return &handler{next: next}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := Tracer().Start(ctx, r.Method+" "+r.URL.Path,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(r.Method),
+			semconv.HTTPTargetKey.String(r.URL.RequestURI()),
+		),
+	)
+	defer span.End()
+
+	recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+	h.next.ServeHTTP(recorder, r.WithContext(ctx))
+
+	span.SetAttributes(
+		semconv.HTTPStatusCodeKey.Int(recorder.status),
+		attribute.Int64("http.response_size", recorder.size),
+	)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(recorder.status, trace.SpanKindServer))
+}
+
+// Records status and size of response. Websocket upgrades and event streams are passed through
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
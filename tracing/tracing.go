@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TracerName  = "openapi-to-graphql"
+	ServiceName = "openapi-to-graphql"
+)
+
+// Span attributes of upstream operation
+const (
+	OperationIdKey = "openapi.operation_id"
+	URLTemplateKey = "http.url_template"
+)
+
+// Exporters supported by NewExporter
+const (
+	NoExporter     = ""
+	StdoutExporter = "stdout"
+	OtlpExporter   = "otlp"
+)
+
+// Returns tracer of global tracer provider, spans are not recorded until Setup is called
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Returns exporter by name. OTLP exporter is configured by OTEL_EXPORTER_OTLP_* environment variables.
+// Returns nil exporter if name is empty
+func NewExporter(ctx context.Context, name string) (sdktrace.SpanExporter, error) {
+	switch name {
+	case NoExporter:
+		return nil, nil
+	case StdoutExporter:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case OtlpExporter:
+		return otlptracehttp.New(ctx)
+	}
+	return nil, errors.New("unknown trace exporter " + name)
+}
+
+// Registers global tracer provider which exports spans with exporter, and W3C trace context propagator.
+// Spans are exported synchronously if sync is set, e.g. to in-memory exporter in tests.
+// Returned function flushes and stops exporter
+func Setup(exporter sdktrace.SpanExporter, sync bool) func(context.Context) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if exporter == nil {
+		return func(context.Context) error { return nil }
+	}
+
+	exportOption := sdktrace.WithBatcher(exporter)
+	if sync {
+		exportOption = sdktrace.WithSyncer(exporter)
+	}
+	provider := sdktrace.NewTracerProvider(
+		exportOption,
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown
+}
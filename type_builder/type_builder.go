@@ -2,8 +2,8 @@ package typebuilder
 
 import (
 	"reflect"
+	"sort"
 	"strconv"
-	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/graphql-go/graphql"
@@ -11,44 +11,70 @@ import (
 	"github.com/graphql-go/graphql/language/kinds"
 	"github.com/jinzhu/copier"
 
+	"openapi-to-graphql/naming"
 	types "openapi-to-graphql/types"
 	"openapi-to-graphql/utils"
 )
 
-var defs = make(map[string]*types.DataDefinition)
+const enumDescriptionsExtension = "x-enum-descriptions"
 
 type UsedOT map[string]graphql.Type // graphql.Type can be field of types.DataDefinition struct schema, datadefs, subdefs and prefered gql type name
 
-var usedOT = make(UsedOT)
+// Creates GraphQL types of translated specs. Types of specs translated by the same builder share names,
+// equal schemas reuse types and different schemas get available names, e.g. Pet2
+type Builder struct {
+	defs map[string]*types.DataDefinition
+	// ID types setting of translation which created definition, types of other setting are not reused
+	defIDTypes map[string]bool
+	usedOT     UsedOT
+	// Names types and enum values of translated spec
+	namer types.Namer
+	// Prefix of names of created types, e.g. Billing of BillingInvoice
+	typePrefix string
+	// Maps id-like properties and parameters to ID type
+	idTypes bool
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		defs:       make(map[string]*types.DataDefinition),
+		defIDTypes: make(map[string]bool),
+		usedOT:     make(UsedOT),
+		namer:      naming.NewDefaultNamer(),
+	}
+}
 
-func setUsedOT(def *types.DataDefinition) {
-	usedOT[def.GraphQLTypeName] = def.GraphQLType
-	usedOT[def.GraphQLInputTypeName] = def.InputGraphQLType
+func (b *Builder) setUsedOT(def *types.DataDefinition) {
+	b.usedOT[def.GraphQLTypeName] = def.GraphQLType
+	b.usedOT[def.GraphQLInputTypeName] = def.InputGraphQLType
 }
 
-func assignGraphQLTypeToDefinition(def *types.DataDefinition) {
-	if usedOT[def.GraphQLTypeName] != nil {
-		def.GraphQLType = usedOT[def.GraphQLTypeName]
-		def.InputGraphQLType = usedOT[def.GraphQLInputTypeName]
+func (b *Builder) assignGraphQLTypeToDefinition(def *types.DataDefinition) {
+	if def.ID {
+		def.GraphQLType = graphql.ID
+		def.InputGraphQLType = graphql.ID
+	} else if b.usedOT[def.GraphQLTypeName] != nil {
+		def.GraphQLType = b.usedOT[def.GraphQLTypeName]
+		def.InputGraphQLType = b.usedOT[def.GraphQLInputTypeName]
 	} else if def.TargetGraphQLType == types.List {
-		assignGraphQLTypeToDefinition(def.ListItemDefinitions)
+		b.assignGraphQLTypeToDefinition(def.ListItemDefinitions)
 
 		def.GraphQLType = graphql.NewList(def.ListItemDefinitions.GraphQLType)
 		def.InputGraphQLType = graphql.NewList(def.ListItemDefinitions.InputGraphQLType)
-		setUsedOT(def.ListItemDefinitions)
+		b.setUsedOT(def.ListItemDefinitions)
 	} else if def.TargetGraphQLType == types.Object {
-		def.GraphQLType = assignOt(def)
+		def.GraphQLType = b.assignOt(def)
 		def.InputGraphQLType = assignInputOt(def)
-		setUsedOT(def)
+		b.setUsedOT(def)
 	} else if def.TargetGraphQLType == types.Enum {
-		def.GraphQLType = assignEnum(def)
+		def.GraphQLType = b.assignEnum(def)
 		def.InputGraphQLType = def.GraphQLType
-		setUsedOT(def)
+		b.setUsedOT(def)
 	} else if def.TargetGraphQLType == types.Union {
 		def.GraphQLType = assignUnion(def)
 		// input type cannot be union
 		def.InputGraphQLType = JSONScalar
-		setUsedOT(def)
+		b.setUsedOT(def)
 	} else if def.TargetGraphQLType == types.JSON {
 		def.GraphQLType = JSONScalar
 		def.InputGraphQLType = JSONScalar
@@ -72,18 +98,18 @@ func assignGraphQLTypeToDefinition(def *types.DataDefinition) {
 	}
 }
 
-func CreateDataDefinition(oas *openapi3.T, schemaRef *openapi3.SchemaRef, schemaNames types.SchemaNames, path string, required bool) *types.DataDefinition {
-	preferredName := getPreferredName(schemaNames)
+func (b *Builder) CreateDataDefinition(oas *openapi3.T, schemaRef *openapi3.SchemaRef, schemaNames types.SchemaNames, path string, required bool) *types.DataDefinition {
+	preferredName := b.getPreferredName(schemaNames, schemaRef.Value)
 	targetGraphQLType := getTargetGraphQLType(schemaRef.Value)
 
 	if targetGraphQLType == types.Union {
 		preferredName += "Union"
 	}
 
-	availableName := getAvailableTypeName(preferredName, preferredName, schemaRef.Value, 1)
+	availableName := b.getAvailableTypeName(preferredName, preferredName, schemaRef.Value, 1)
 
-	if defs[availableName] != nil {
-		return defs[availableName]
+	if b.defs[availableName] != nil {
+		return b.defs[availableName]
 	}
 
 	def := types.DataDefinition{
@@ -93,21 +119,22 @@ func CreateDataDefinition(oas *openapi3.T, schemaRef *openapi3.SchemaRef, schema
 		Schema:               schemaRef.Value,
 		Names:                schemaNames,
 		GraphQLTypeName:      availableName,
-		GraphQLInputTypeName: availableName + "Input",
+		GraphQLInputTypeName: b.namer.InputType(availableName),
 		Required:             schemaRef.Value.Nullable || required,
 		TargetGraphQLType:    targetGraphQLType,
 		Type:                 schemaRef.Value.Type,
 	}
 
 	if len(availableName) > 0 && (targetGraphQLType == types.Object || targetGraphQLType == types.Union || targetGraphQLType == types.Enum || targetGraphQLType == types.List) {
-		defs[availableName] = &def
+		b.defs[availableName] = &def
+		b.defIDTypes[availableName] = b.idTypes
 	}
 
 	if targetGraphQLType == types.List {
 		names := types.SchemaNames{
 			FromRef: utils.GetRefName(schemaRef.Value.Items.Ref),
 		}
-		subDef := CreateDataDefinition(oas, schemaRef.Value.Items, names, path, false)
+		subDef := b.CreateDataDefinition(oas, schemaRef.Value.Items, names, path, false)
 		def.ListItemDefinitions = subDef
 	} else if targetGraphQLType == types.Object {
 		objectDefinitions := make(map[string]*types.DataDefinition)
@@ -130,28 +157,30 @@ func CreateDataDefinition(oas *openapi3.T, schemaRef *openapi3.SchemaRef, schema
 					names.FromSchema = utils.ToPascalCase(fieldName)
 				}
 				required := utils.Contains(schemaRef.Value.Required, fieldName)
-				subDefinition := CreateDataDefinition(oas, value, names, path, required)
+				subDefinition := b.CreateDataDefinition(oas, value, names, path, required)
+				b.AssignIDType(subDefinition, fieldName)
 				objectDefinitions[fieldName] = subDefinition
 			}
 		}
 
 		def.ObjectPropertiesDefinitions = objectDefinitions
+		def.FieldNames = b.getFieldNames(&def)
 	} else if targetGraphQLType == types.Union {
-		def.UnionDefinitions = createUnionDefinitions(oas, schemaRef, schemaNames, path, required)
+		def.UnionDefinitions = b.createUnionDefinitions(oas, schemaRef, schemaNames, path, required)
 	}
 
-	assignGraphQLTypeToDefinition(&def)
+	b.assignGraphQLTypeToDefinition(&def)
 
 	return &def
 }
 
-func createUnionDefinitions(oas *openapi3.T, schemaRef *openapi3.SchemaRef, schemaNames types.SchemaNames, path string, required bool) []*types.DataDefinition {
+func (b *Builder) createUnionDefinitions(oas *openapi3.T, schemaRef *openapi3.SchemaRef, schemaNames types.SchemaNames, path string, required bool) []*types.DataDefinition {
 	schemaWithoutOneOf := &openapi3.SchemaRef{}
 	copier.Copy(&schemaWithoutOneOf, &schemaRef)
 	schemaWithoutOneOf.Value.OneOf = nil
 
 	definitions := make([]*types.DataDefinition, 0)
-	baseDefinition := CreateDataDefinition(oas, schemaWithoutOneOf, schemaNames, path, required)
+	baseDefinition := b.CreateDataDefinition(oas, schemaWithoutOneOf, schemaNames, path, required)
 
 	if baseDefinition.GraphQLType != nil {
 		definitions = append(definitions, baseDefinition)
@@ -163,7 +192,7 @@ func createUnionDefinitions(oas *openapi3.T, schemaRef *openapi3.SchemaRef, sche
 			FromSchema: oneOfSchema.Value.Title,
 			FromPath:   path,
 		}
-		memberTypeDefinition := CreateDataDefinition(oas, oneOfSchema, names, path, required)
+		memberTypeDefinition := b.CreateDataDefinition(oas, oneOfSchema, names, path, required)
 		if memberTypeDefinition.GraphQLType != nil {
 			definitions = append(definitions, memberTypeDefinition)
 		}
@@ -280,16 +309,138 @@ func assignUnion(def *types.DataDefinition) graphql.Type {
 	return def.GraphQLType
 }
 
-func assignEnum(def *types.DataDefinition) graphql.Type {
-	enumConfigMap := graphql.EnumValueConfigMap{}
+// Key of the response object which holds the name of the result union member selected by status code
+const ResultTypeKey = "__resultType"
+
+// Returns object type of a definition or nil if definition can't be a union member
+func GetObjectType(def *types.DataDefinition) *graphql.Object {
+	if def == nil || def.TargetGraphQLType != types.Object || def.GraphQLType == nil {
+		return nil
+	}
+	object, _ := graphql.GetNullable(def.GraphQLType).(*graphql.Object)
+	return object
+}
+
+func (b *Builder) CreateResultUnion(result *types.ResultDefinition) graphql.Type {
+	if b.usedOT[result.GraphQLTypeName] != nil {
+		return b.usedOT[result.GraphQLTypeName]
+	}
+
+	objectTypes := []*graphql.Object{GetObjectType(result.Success)}
+	for _, e := range result.Errors {
+		object := GetObjectType(e.DataDefinition)
+		if !containsObject(objectTypes, object) {
+			objectTypes = append(objectTypes, object)
+		}
+	}
+
+	union := graphql.NewUnion(graphql.UnionConfig{
+		Name:        result.GraphQLTypeName,
+		Description: result.Success.Schema.Description,
+		Types:       objectTypes,
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			value, ok := p.Value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			for _, object := range objectTypes {
+				if object.Name() == value[ResultTypeKey] {
+					return object
+				}
+			}
+			return nil
+		},
+	})
+	b.usedOT[result.GraphQLTypeName] = union
+
+	return union
+}
+
+func (b *Builder) CreateResponseWrapper(wrapper *types.ResponseWrapperDefinition, dataType graphql.Type) graphql.Type {
+	if b.usedOT[wrapper.GraphQLTypeName] != nil {
+		return b.usedOT[wrapper.GraphQLTypeName]
+	}
+
+	fields := graphql.Fields{
+		"data":   &graphql.Field{Type: dataType},
+		"status": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "HTTP status code of upstream response"},
+	}
 
-	for _, v := range def.Schema.Enum {
-		value := utils.CastToString(v)
-		if len(value) > 0 {
-			enumConfigMap[strings.ToUpper(value)] = &graphql.EnumValueConfig{
-				Value: value,
+	if len(wrapper.Headers) > 0 {
+		headerFields := graphql.Fields{}
+		for _, h := range wrapper.Headers {
+			headerFields[h.FieldName] = &graphql.Field{
+				Type:        h.DataDefinition.GraphQLType,
+				Description: h.HeaderName,
 			}
 		}
+		fields["headers"] = &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+				Name:   wrapper.HeadersGraphQLTypeName,
+				Fields: headerFields,
+			})),
+		}
+	}
+
+	object := graphql.NewObject(graphql.ObjectConfig{
+		Name:   wrapper.GraphQLTypeName,
+		Fields: fields,
+	})
+	b.usedOT[wrapper.GraphQLTypeName] = object
+
+	return object
+}
+
+// Returns Relay connection type of paginated list items, e.g. PetConnection with PetEdge edges
+func (b *Builder) CreateConnection(pagination *types.PaginationDefinition) graphql.Type {
+	itemDef := pagination.ItemDefinition
+	connectionName := itemDef.GraphQLTypeName + "Connection"
+	if b.usedOT[connectionName] != nil {
+		return b.usedOT[connectionName]
+	}
+
+	edge := graphql.NewObject(graphql.ObjectConfig{
+		Name: itemDef.GraphQLTypeName + "Edge",
+		Fields: graphql.Fields{
+			"node":   &graphql.Field{Type: itemDef.GraphQLType},
+			"cursor": &graphql.Field{Type: graphql.String},
+		},
+	})
+	connection := graphql.NewObject(graphql.ObjectConfig{
+		Name: connectionName,
+		Fields: graphql.Fields{
+			"edges":      &graphql.Field{Type: graphql.NewList(edge)},
+			"pageInfo":   &graphql.Field{Type: graphql.NewNonNull(PageInfoObject)},
+			"totalCount": &graphql.Field{Type: graphql.Int},
+		},
+	})
+	b.usedOT[connectionName] = connection
+
+	return connection
+}
+
+func containsObject(objects []*graphql.Object, object *graphql.Object) bool {
+	for _, o := range objects {
+		if o.Name() == object.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// Enum values keep original JSON values of any type, they are serialized back to the same values
+func (b *Builder) assignEnum(def *types.DataDefinition) graphql.Type {
+	enumConfigMap := graphql.EnumValueConfigMap{}
+
+	for i, value := range def.Schema.Enum {
+		// null is not an enum value, nullable enum is nullable type
+		if value == nil {
+			continue
+		}
+		enumConfigMap[b.namer.EnumValue(def.GraphQLTypeName, def.Schema, i)] = &graphql.EnumValueConfig{
+			Value:       value,
+			Description: getEnumDescription(def.Schema, i),
+		}
 	}
 
 	return graphql.NewEnum(graphql.EnumConfig{
@@ -298,14 +449,107 @@ func assignEnum(def *types.DataDefinition) graphql.Type {
 	})
 }
 
-func assignOt(def *types.DataDefinition) graphql.Type {
+// Returns description of enum value from x-enum-descriptions extension, array in order of enum or object by value
+func getEnumDescription(schema *openapi3.Schema, index int) string {
+	var descriptions []string
+	if utils.GetExtension(schema.Extensions, enumDescriptionsExtension, &descriptions) && index < len(descriptions) {
+		return descriptions[index]
+	}
+	var descriptionsByValue map[string]string
+	if utils.GetExtension(schema.Extensions, enumDescriptionsExtension, &descriptionsByValue) {
+		return descriptionsByValue[utils.EnumValueString(schema.Enum[index])]
+	}
+	return ""
+}
+
+// Returns GraphQL field names by property names. Valid names are assigned first, renamed properties don't take them
+func (b *Builder) getFieldNames(def *types.DataDefinition) map[string]string {
+	properties := make([]string, 0, len(def.ObjectPropertiesDefinitions))
+	for property := range def.ObjectPropertiesDefinitions {
+		properties = append(properties, property)
+	}
+	sort.Slice(properties, func(i, j int) bool {
+		if validI, validJ := utils.IsValidName(properties[i]), utils.IsValidName(properties[j]); validI != validJ {
+			return validI
+		}
+		return properties[i] < properties[j]
+	})
+
+	fieldNames := make(map[string]string)
+	for _, property := range properties {
+		fieldNames[property] = b.namer.Property(def.GraphQLTypeName, property)
+	}
+	return fieldNames
+}
+
+// Returns GraphQL field name of object property
+func GetFieldName(def *types.DataDefinition, property string) string {
+	if name, ok := def.FieldNames[property]; ok {
+		return name
+	}
+	return property
+}
+
+// Returns argument value with original property names of input objects, e.g. created-at of created_at, and integer IDs
+func GetUpstreamValue(def *types.DataDefinition, value interface{}) interface{} {
+	if def == nil {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if def.TargetGraphQLType != types.Object {
+			return value
+		}
+		result := make(map[string]interface{}, len(v))
+		for property, p := range def.ObjectPropertiesDefinitions {
+			if fieldValue, ok := v[GetFieldName(def, property)]; ok {
+				result[property] = GetUpstreamValue(p, fieldValue)
+			}
+		}
+		return result
+	case []interface{}:
+		if def.TargetGraphQLType != types.List {
+			return value
+		}
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			result = append(result, GetUpstreamValue(def.ListItemDefinitions, item))
+		}
+		return result
+	case string:
+		// ID of integer property is sent as integer, invalid integer is sent as is
+		if def.ID && def.TargetGraphQLType == types.Integer {
+			if id, err := strconv.Atoi(v); err == nil {
+				return id
+			}
+		}
+	}
+	return value
+}
+
+// Resolves renamed field from original property of upstream object
+func resolveProperty(property string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if source, ok := p.Source.(map[string]interface{}); ok {
+			return source[property], nil
+		}
+		return nil, nil
+	}
+}
+
+func (b *Builder) assignOt(def *types.DataDefinition) graphql.Type {
 	def.GraphQLObject = graphql.NewObject(graphql.ObjectConfig{
 		Name: def.GraphQLTypeName,
 		Fields: graphql.FieldsThunk(func() graphql.Fields {
 			fields := graphql.Fields{}
-			for fieldName, p := range def.ObjectPropertiesDefinitions {
-				assignGraphQLTypeToDefinition(p)
-				fields[fieldName] = &graphql.Field{Type: p.GraphQLType, Name: fieldName}
+			for property, p := range def.ObjectPropertiesDefinitions {
+				b.assignGraphQLTypeToDefinition(p)
+				fieldName := GetFieldName(def, property)
+				field := &graphql.Field{Type: p.GraphQLType, Name: fieldName}
+				if fieldName != property {
+					field.Resolve = resolveProperty(property)
+				}
+				fields[fieldName] = field
 			}
 			return fields
 		}),
@@ -320,8 +564,8 @@ func assignInputOt(def *types.DataDefinition) graphql.Type {
 			Fields: graphql.InputObjectConfigFieldMapThunk(
 				func() graphql.InputObjectConfigFieldMap {
 					fields := graphql.InputObjectConfigFieldMap{}
-					for fieldName, p := range def.ObjectPropertiesDefinitions {
-						fields[fieldName] = &graphql.InputObjectFieldConfig{Type: p.InputGraphQLType}
+					for property, p := range def.ObjectPropertiesDefinitions {
+						fields[GetFieldName(def, property)] = &graphql.InputObjectFieldConfig{Type: p.InputGraphQLType}
 					}
 					return fields
 				},
@@ -330,31 +574,56 @@ func assignInputOt(def *types.DataDefinition) graphql.Type {
 	)
 }
 
-func getPreferredName(names types.SchemaNames) string {
-	preferredName := ""
+func (b *Builder) getPreferredName(names types.SchemaNames, schema *openapi3.Schema) string {
+	return b.PrefixTypeName(b.namer.Type(names, schema))
+}
+
+// Sets namer of translated spec, default namer if nil
+func (b *Builder) SetNamer(n types.Namer) {
+	if n == nil {
+		n = naming.NewDefaultNamer()
+	}
+	b.namer = n
+}
+
+// Sets prefix of names of types of translated spec
+func (b *Builder) SetTypePrefix(prefix string) {
+	b.typePrefix = utils.ToPascalCase(prefix)
+}
+
+// Sets mapping of id-like properties and parameters of translated spec to ID type
+func (b *Builder) SetIDTypes(enabled bool) {
+	b.idTypes = enabled
+}
 
-	if len(names.FromRef) > 0 {
-		preferredName = names.FromRef
-	} else if len(names.FromSchema) > 0 {
-		preferredName = names.FromSchema
-	} else if len(names.FromPath) > 0 {
-		preferredName = names.FromPath
+// Changes type of integer or string definition of id-like property or parameter to ID if ID types are enabled
+func (b *Builder) AssignIDType(def *types.DataDefinition, name string) {
+	if !b.idTypes || !utils.IsIdName(name) || (def.TargetGraphQLType != types.Integer && def.TargetGraphQLType != types.String) {
+		return
 	}
+	def.ID = true
+	b.assignGraphQLTypeToDefinition(def)
+}
 
-	return utils.ToPascalCase(preferredName)
+// Returns type name with prefix of translated spec
+func (b *Builder) PrefixTypeName(name string) string {
+	return b.typePrefix + name
 }
 
+// Names of root and built-in types
+var reservedTypeNames = []string{"Query", "Mutation", "Subscription", "JSON", "File", "PageInfo"}
+
 // Returns available name of gql type. If type already exists returns preferredName + "i"
-func getAvailableTypeName(preferredName string, previousName string, schema *openapi3.Schema, i int) string {
-	if defs[preferredName] != nil {
+func (b *Builder) getAvailableTypeName(preferredName string, previousName string, schema *openapi3.Schema, i int) string {
+	if b.defs[preferredName] != nil || utils.Contains(reservedTypeNames, preferredName) {
 		// if schemas are deep equal reuse name
-		if reflect.DeepEqual(defs[preferredName].Schema, schema) {
+		if b.defs[preferredName] != nil && reflect.DeepEqual(b.defs[preferredName].Schema, schema) && b.defIDTypes[preferredName] == b.idTypes {
 			return preferredName
 		} else {
 			i += 1
 			// add number to the end of string and check again. We need previous name to do not mutate current
 			preferredName = previousName + strconv.Itoa(i)
-			return getAvailableTypeName(preferredName, previousName, schema, i)
+			return b.getAvailableTypeName(preferredName, previousName, schema, i)
 		}
 	} else {
 		return preferredName
@@ -390,6 +659,54 @@ func parseLiteral(astValue ast.Value) interface{} {
 	}
 }
 
+// Relay PageInfo type
+var PageInfoObject = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"hasPreviousPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"startCursor":     &graphql.Field{Type: graphql.String},
+			"endCursor":       &graphql.Field{Type: graphql.String},
+		},
+	},
+)
+
+// File type of binary responses
+var FileObject = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name:        "File",
+		Description: "Binary response, e.g. application/octet-stream or image/png",
+		Fields: graphql.Fields{
+			"contentType": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"size":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"base64":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"url":         &graphql.Field{Type: graphql.String, Description: "URL of file download handler which streams file from upstream"},
+		},
+	},
+)
+
+// Returns definition of binary response. Schema is optional for binary content
+func CreateFileDefinition(oas *openapi3.T, schemaRef *openapi3.SchemaRef, path string) *types.DataDefinition {
+	if schemaRef == nil || schemaRef.Value == nil {
+		schemaRef = openapi3.NewBytesSchema().NewRef()
+	}
+
+	return &types.DataDefinition{
+		Path:                 path,
+		OAS:                  oas,
+		SchemaRef:            schemaRef,
+		Schema:               schemaRef.Value,
+		GraphQLTypeName:      FileObject.Name(),
+		GraphQLInputTypeName: FileObject.Name() + "Input",
+		TargetGraphQLType:    types.Object,
+		Type:                 schemaRef.Value.Type,
+		GraphQLObject:        FileObject,
+		GraphQLType:          FileObject,
+		InputGraphQLType:     graphql.String,
+	}
+}
+
 // JSON type
 var JSONScalar = graphql.NewScalar(
 	graphql.ScalarConfig{
@@ -0,0 +1,13 @@
+package types
+
+// Selects operations matching any of tags, path globs, HTTP methods, operationIds or x-internal extension
+type OperationSelector struct {
+	Tags []string
+	// Globs of paths, * matches path segment and ** any number of segments, e.g. /admin/**
+	Paths []string
+	// HTTP methods, case insensitive
+	Methods      []string
+	OperationIds []string
+	// Operations with x-internal: true extension
+	Internal bool
+}
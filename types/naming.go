@@ -0,0 +1,44 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Names that resolve to the same name in scope, e.g. arguments pet_id and petId of findPets
+type NameCollision struct {
+	// field, argument or enum value
+	Kind string
+	// Root type, field or enum type of name
+	Scope    string
+	Original string
+	Name     string
+	// Name with number suffix given to original name
+	Resolved string
+}
+
+func (c NameCollision) String() string {
+	return fmt.Sprintf("%s %s of %s is named %s, %s is taken", c.Kind, c.Original, c.Scope, c.Resolved, c.Name)
+}
+
+// Namer which records name collisions, they are added to report of translation
+type CollisionRecorder interface {
+	Collisions() []NameCollision
+}
+
+// Names elements of GraphQL schema. The same input should give the same name, specs can be translated more than once
+type Namer interface {
+	// Name of root field of operation, e.g. findPetById
+	OperationField(path string, method string, operation *openapi3.Operation) string
+	// Name of argument of root field from parameter or request body name, e.g. petId
+	Argument(field string, name string, extensions map[string]interface{}) string
+	// Name of type of schema, e.g. Pet. Conflicting types get number suffix after naming
+	Type(names SchemaNames, schema *openapi3.Schema) string
+	// Name of input type of type, e.g. PetInput
+	InputType(typeName string) string
+	// Name of field of object type from property name, e.g. created_at of created-at. Valid names are kept
+	Property(typeName string, name string) string
+	// Name of value of enum type with index in schema.Enum, e.g. IN_PROGRESS of in-progress
+	EnumValue(typeName string, schema *openapi3.Schema, index int) string
+}
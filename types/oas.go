@@ -16,23 +16,32 @@ type RequestContent struct {
 	Content     openapi3.MediaType
 }
 
+type ResponseContent struct {
+	ContentType string
+	Content     openapi3.MediaType
+}
+
 type DataDefinition struct {
-	Path                        string
-	OAS                         *openapi3.T
-	SchemaRef                   *openapi3.SchemaRef
-	Schema                      *openapi3.Schema
-	Names                       SchemaNames
-	GraphQLTypeName             string
-	GraphQLInputTypeName        string
-	Type                        string
-	TargetGraphQLType           int
+	Path                 string
+	OAS                  *openapi3.T
+	SchemaRef            *openapi3.SchemaRef
+	Schema               *openapi3.Schema
+	Names                SchemaNames
+	GraphQLTypeName      string
+	GraphQLInputTypeName string
+	Type                 string
+	TargetGraphQLType    int
+	// Integer or string of id-like property or parameter mapped to ID type
+	ID                          bool
 	Required                    bool
 	ObjectPropertiesDefinitions map[string]*DataDefinition
-	ListItemDefinitions         *DataDefinition
-	UnionDefinitions            []*DataDefinition
-	GraphQLObject               *graphql.Object
-	GraphQLType                 graphql.Type
-	InputGraphQLType            graphql.Type
+	// GraphQL field names by property names, properties which are not valid names are renamed
+	FieldNames          map[string]string
+	ListItemDefinitions *DataDefinition
+	UnionDefinitions    []*DataDefinition
+	GraphQLObject       *graphql.Object
+	GraphQLType         graphql.Type
+	InputGraphQLType    graphql.Type
 }
 
 type SchemaNames struct {
@@ -40,3 +49,69 @@ type SchemaNames struct {
 	FromSchema string
 	FromPath   string
 }
+
+type ErrorResponseDefinition struct {
+	StatusCode     string
+	DataDefinition *DataDefinition
+}
+
+// Union of success and error response types of a single operation
+type ResultDefinition struct {
+	GraphQLTypeName string
+	Success         *DataDefinition
+	Errors          []*ErrorResponseDefinition
+}
+
+// Upstream operation called by a field resolver
+type OperationDefinition struct {
+	// Name of root field
+	OperationName string
+	// operationId of spec, empty if operation has none
+	OperationID           string
+	Description           string
+	OperationType         int
+	ServerUrl             string
+	Path                  string
+	HttpMethod            string
+	ArgToParam            map[string]*openapi3.ParameterRef
+	ArgDefinitions        map[string]*DataDefinition
+	RequestBodyDefinition *RequestBodyDefinition
+	ResponseContentType   string
+	ResponseDefinition    *DataDefinition
+	ResultDefinition      *ResultDefinition
+	// Response has no body, e.g. 204 No Content
+	NoContent                 bool
+	ResponseWrapperDefinition *ResponseWrapperDefinition
+	PaginationDefinition      *PaginationDefinition
+}
+
+type ResponseHeaderDefinition struct {
+	HeaderName     string
+	FieldName      string
+	DataDefinition *DataDefinition
+}
+
+// Type with data, headers and status of operation response
+type ResponseWrapperDefinition struct {
+	GraphQLTypeName        string
+	HeadersGraphQLTypeName string
+	Headers                []*ResponseHeaderDefinition
+}
+
+// Mapping of Relay connection to upstream paging parameters and response fields
+type PaginationDefinition struct {
+	Style            string
+	LimitArgName     string
+	OffsetArgName    string
+	PageArgName      string
+	CursorArgName    string
+	CursorParamName  string
+	PageSize         int
+	ItemsField       string
+	HasNextPageField string
+	NextCursorField  string
+	NextCursorHeader string
+	TotalCountField  string
+	TotalCountHeader string
+	ItemDefinition   *DataDefinition
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Source of payloads of callback requests received by webhook receiver, e.g. subscriptions.PubSub.
+// Channel of topic is closed when context is done
+type Webhooks interface {
+	Subscribe(ctx context.Context, topic string) <-chan interface{}
+}
+
+// Options tweaks the translation of an OpenAPI document to a GraphQL schema
+type Options struct {
+	// Return a union of success and documented error response types from every operation
+	ErrorUnions bool
+	// Wrap result of every operation to { data, headers, status } type with headers declared on success response
+	ResponseWrappers bool
+	// Return Relay connections from list operations with paging parameters. Detected by heuristics or x-graphql-pagination extension
+	Pagination bool
+	// Base URL of webhook receiver passed to upstream as callback URL, e.g. http://localhost:8080/webhooks.
+	// Subscriptions are generated from operation callbacks when set
+	WebhookURL string
+	// Routes callback requests received by webhook receiver to subscribers
+	Webhooks Webhooks
+	// Base URL of file download handler, e.g. /files. File url field is null when empty
+	FileDownloadURL string
+	// Names of query fields exposed as subscriptions which poll upstream and send result when response changes
+	PollingSubscriptions []string
+	// Interval of polling subscriptions, subscriptions.DefaultPollInterval if not set
+	PollInterval time.Duration
+	// Resolve operations with generated data instead of calling upstream. Subscriptions and file downloads are disabled
+	Mock bool
+	// Seed of generated data, the same seed and arguments give the same result
+	MockSeed int64
+	// Add Apollo Federation v2 _service and _entities fields and @key directives of entity types
+	Federation bool
+	// Key fields of entity types by GraphQL type name, e.g. Pet: "id". Also set by x-graphql-key extension of schema
+	FederationKeys map[string]string
+	// Group query and mutation fields in namespace fields by first tag of operation, e.g. query { pets { findPets } }
+	TagNamespaces bool
+	// Names fields, arguments, types and enum values, naming.DefaultNamer of camel case strategy if not set
+	Namer Namer
+	// Only operations matching selector are translated if set
+	Include *OperationSelector
+	// Operations matching selector are not translated, e.g. &OperationSelector{Internal: true}
+	Exclude *OperationSelector
+	// Receives outcome of translation of every operation if set
+	Report *Report
+	// Base URL of upstream, overrides servers of spec, paths and operations
+	ServerURL string
+	// Index of server of spec, the first server if not set. Servers of paths and operations are preferred
+	ServerIndex int
+	// Values of server variables by name, override defaults of spec, e.g. region: eu
+	ServerVariables map[string]string
+	// URL the spec was loaded from. Relative server URLs are resolved against it
+	SpecURL string
+	// Headers added to every upstream request, e.g. Authorization
+	Headers http.Header
+	// Prefix of names of GraphQL types, e.g. Billing of BillingInvoice. Resolves conflicts of merged specs
+	TypePrefix string
+	// Map id-like properties and parameters, e.g. id or petId, to ID type. Values are sent to upstream as integer or string of schema
+	IDTypes bool
+	// Transport of upstream requests, e.g. cassette.Transport. http.DefaultTransport if not set
+	Transport http.RoundTripper
+}
@@ -0,0 +1,7 @@
+package types
+
+const (
+	OffsetPagination = "offset"
+	PagePagination   = "page"
+	CursorPagination = "cursor"
+)
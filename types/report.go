@@ -0,0 +1,63 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	OperationTranslated = "translated"
+	OperationFiltered   = "filtered"
+	OperationSkipped    = "skipped"
+)
+
+// Outcome of translation of every operation of spec
+type Report struct {
+	Operations []*OperationReport
+	Collisions []NameCollision
+}
+
+type OperationReport struct {
+	Path          string
+	Method        string
+	OperationName string
+	// translated, filtered or skipped
+	Status string
+	// Rule which filtered operation out, error of skipped operation or note of translated operation
+	Reason string
+}
+
+func (r *Report) Add(operation *OperationReport) {
+	r.Operations = append(r.Operations, operation)
+}
+
+func (r *Report) AddCollisions(collisions ...NameCollision) {
+	r.Collisions = append(r.Collisions, collisions...)
+}
+
+// Returns operations with status
+func (r *Report) Filter(status string) []*OperationReport {
+	result := make([]*OperationReport, 0)
+	for _, operation := range r.Operations {
+		if operation.Status == status {
+			result = append(result, operation)
+		}
+	}
+	return result
+}
+
+// Returns line of every operation and name collision, e.g. filtered GET /admin/users (listUsers): excluded by path /admin/**
+func (r *Report) String() string {
+	b := &strings.Builder{}
+	for _, operation := range r.Operations {
+		fmt.Fprintf(b, "%s %s %s (%s)", operation.Status, strings.ToUpper(operation.Method), operation.Path, operation.OperationName)
+		if len(operation.Reason) > 0 {
+			b.WriteString(": " + operation.Reason)
+		}
+		b.WriteString("\n")
+	}
+	for _, collision := range r.Collisions {
+		b.WriteString("collision " + collision.String() + "\n")
+	}
+	return b.String()
+}
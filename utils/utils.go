@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -22,6 +25,28 @@ func Contains(strings []string, str string) bool {
 	return false
 }
 
+// Decodes value of specification extension, e.g. x-graphql-pagination. Returns false if extension is not set or invalid
+func GetExtension(extensions map[string]interface{}, name string, value interface{}) bool {
+	raw, ok := extensions[name]
+	if !ok {
+		return false
+	}
+	var data []byte
+	switch v := raw.(type) {
+	case json.RawMessage:
+		data = v
+	case []byte:
+		data = v
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return false
+		}
+		data = encoded
+	}
+	return json.Unmarshal(data, value) == nil
+}
+
 func GetRefName(ref string) string {
 	arr := strings.Split(ref, "/")
 
@@ -58,13 +83,23 @@ func Sanitize(s string) string {
 	return reg.ReplaceAllString(s, "")
 }
 
-func GetServerUrl(oas *openapi3.T) string {
-	for _, s := range oas.Servers {
-		if len(s.URL) > 0 {
-			return s.URL
-		}
+// Returns sorted paths of spec, e.g. /pets/{id}
+func GetPaths(oas *openapi3.T) []string {
+	paths := make([]string, 0, len(oas.Paths))
+	for path := range oas.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Returns path of absolute or relative URL with trailing slash, e.g. /files/ for http://localhost:8080/files
+func GetURLPath(rawURL string) string {
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
 	}
-	panic("Server URL not found")
+	return strings.TrimSuffix(path, "/") + "/"
 }
 
 // have to find better solution to convert interface{} to query string..
@@ -77,6 +112,8 @@ func Serialize(data interface{}, key string) string {
 		return key + "=" + CastToString(data)
 	case int:
 		return key + "=" + CastToString(data)
+	case float64:
+		return key + "=" + CastToString(data)
 	case []interface{}:
 		arr := data.([]interface{})
 		result := []string{}
@@ -98,7 +135,7 @@ func Serialize(data interface{}, key string) string {
 	}
 }
 
-// converts interface{string || bool || int} to string
+// converts interface{string || bool || int || float64} to string
 func CastToString(s interface{}) string {
 	switch s.(type) {
 	// Should we cover another cases?
@@ -108,11 +145,27 @@ func CastToString(s interface{}) string {
 		return strconv.FormatBool(s.(bool))
 	case int:
 		return strconv.Itoa(s.(int))
+	case float64:
+		return strconv.FormatFloat(s.(float64), 'f', -1, 64)
 	default:
 		return ""
 	}
 }
 
+// Returns enum value of any JSON type as string, e.g. 1.5, true or in-progress
+func EnumValueString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return "null"
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
 func InferResourceNameFromPath(path string) string {
 	pluralizeClient := pluralize.NewClient()
 	parts := strings.Split(path, "/")
@@ -121,7 +174,7 @@ func InferResourceNameFromPath(path string) string {
 
 	for i, part := range parts {
 		if !openBracket.MatchString(part) {
-			if i+1 < len(parts) && len(parts[i+1]) > 0 && (isIdParam(parts[i+1]) || isSingularParam(part, parts[i+1])) {
+			if i+1 < len(parts) && len(parts[i+1]) > 0 && (IsIdParam(parts[i+1]) || isSingularParam(part, parts[i+1])) {
 				result += strings.Title(pluralizeClient.Singular(part))
 			} else {
 				result += strings.Title(part)
@@ -131,11 +184,23 @@ func InferResourceNameFromPath(path string) string {
 	return result
 }
 
-func isIdParam(part string) bool {
+// Returns true if path segment is id-like parameter, e.g. {id}
+func IsIdParam(part string) bool {
 	possibleId := regexp.MustCompile(`\{.*(id|name|key)*\}`)
 	return possibleId.MatchString(part)
 }
 
+// Returns true if name is valid GraphQL name which is not reserved for introspection, e.g. createdAt or _type
+func IsValidName(name string) bool {
+	validName := regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+	return validName.MatchString(name) && !strings.HasPrefix(name, "__")
+}
+
+// Returns true if property or parameter name is id-like, e.g. id, petId or pet_id
+func IsIdName(name string) bool {
+	return strings.EqualFold(name, "id") || strings.HasSuffix(name, "Id") || strings.HasSuffix(name, "ID") || strings.HasSuffix(strings.ToLower(name), "_id")
+}
+
 func isSingularParam(part string, nextPart string) bool {
 	return "{"+pluralize.NewClient().Singular(part)+"}" == nextPart
 }
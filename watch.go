@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"openapi-to-graphql/loader"
+	"openapi-to-graphql/types"
+)
+
+// Handler of the current schema. Reload swaps the schema, in-flight requests are finished by the previous one
+type currentHandler struct {
+	handler atomic.Value
+}
+
+func (h *currentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.Load().(http.Handler).ServeHTTP(w, r)
+}
+
+func (h *currentHandler) swap(handler http.Handler) {
+	h.handler.Store(handler)
+}
+
+// Returns error if spec can't be reloaded, stdin is read only once
+func checkWatchable(specConfigs []*specConfig) error {
+	for _, specConfig := range specConfigs {
+		if specConfig.Path == "-" {
+			return errors.New("Spec of stdin can't be watched")
+		}
+	}
+	return nil
+}
+
+// Reloads specs when modification time of spec file changes or on SIGHUP. Specs of URLs and changed files
+// of external refs are reloaded only on SIGHUP. Schema is swapped only if all specs are loaded and translated
+func watch(current *currentHandler, specConfigs []*specConfig, loadOptions loader.Options, options types.Options, interval time.Duration) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	modTimes := getModTimes(specConfigs)
+	for {
+		select {
+		case <-hangup:
+			log.Print("Reloading specs on SIGHUP")
+			modTimes = getModTimes(specConfigs)
+		case <-ticker.C:
+			changed := getModTimes(specConfigs)
+			if reflect.DeepEqual(changed, modTimes) {
+				continue
+			}
+			modTimes = changed
+			log.Print("Reloading changed specs")
+		}
+
+		if err := reload(current, specConfigs, loadOptions, options); err != nil {
+			log.Print("Reloading specs failed, previous schema is served. " + err.Error())
+			continue
+		}
+		log.Print("Schema reloaded")
+	}
+}
+
+// Returns modification times of spec files. Missing file has zero time, e.g. while editor replaces it
+func getModTimes(specConfigs []*specConfig) map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, specConfig := range specConfigs {
+		if loader.IsURL(specConfig.Path) {
+			continue
+		}
+		var modTime time.Time
+		if info, err := os.Stat(specConfig.Path); err == nil {
+			modTime = info.ModTime()
+		}
+		modTimes[specConfig.Path] = modTime
+	}
+	return modTimes
+}
+
+// Loads and translates specs and swaps schema of current handler. Panic of translation is returned as error
+func reload(current *currentHandler, specConfigs []*specConfig, loadOptions loader.Options, options types.Options) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	docs, err := loadSpecs(specConfigs, loadOptions)
+	if err != nil {
+		return err
+	}
+	handler, err := newSchemaHandler(specConfigs, docs, options)
+	if err != nil {
+		return err
+	}
+	current.swap(handler)
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"openapi-to-graphql/loader"
+	"openapi-to-graphql/types"
+)
+
+const watchedSpec = `{
+	"openapi": "3.0.0",
+	"info": { "title": "Tasks", "version": "1.0.0" },
+	"servers": [{ "url": "%SERVER%" }],
+	"paths": {
+		"%PATH%": {
+			"get": {
+				"operationId": "%OPERATION%",
+				"responses": {
+					"200": {
+						"description": "Task",
+						"content": {
+							"application/json": {
+								"schema": { "type": "object", "properties": { "name": { "type": "string" } } }
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+func TestReload(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"` + strings.TrimPrefix(r.URL.Path, "/") + `"}`))
+	}))
+	defer upstream.Close()
+
+	specConfigs := []*specConfig{{Name: "tasks", Path: filepath.Join(t.TempDir(), "spec.json")}}
+	writeWatchedSpec(t, specConfigs[0].Path, upstream.URL, "/task", "getTask")
+	current := newCurrentHandler(t, specConfigs)
+	assertQuery(t, current, "{ getTask { name } }", `{"data":{"getTask":{"name":"task"}}}`)
+
+	// successful reload swaps schema
+	writeWatchedSpec(t, specConfigs[0].Path, upstream.URL, "/other", "getOther")
+	if err := reload(current, specConfigs, loader.Options{}, types.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	assertQuery(t, current, "{ getOther { name } }", `{"data":{"getOther":{"name":"other"}}}`)
+	if data := query(t, current, "{ getTask { name } }"); !strings.Contains(data, "errors") {
+		t.Errorf("field of previous schema is served: %s", data)
+	}
+
+	// broken spec keeps previous schema
+	if err := ioutil.WriteFile(specConfigs[0].Path, []byte(`{ "openapi": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := reload(current, specConfigs, loader.Options{}, types.Options{}); err == nil {
+		t.Error("reload of broken spec succeeded")
+	}
+	assertQuery(t, current, "{ getOther { name } }", `{"data":{"getOther":{"name":"other"}}}`)
+}
+
+func TestReloadInFlight(t *testing.T) {
+	started := make(chan bool)
+	release := make(chan bool)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/task" {
+			close(started)
+			select {
+			case <-release:
+			case <-time.After(5 * time.Second):
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"` + strings.TrimPrefix(r.URL.Path, "/") + `"}`))
+	}))
+	defer upstream.Close()
+
+	specConfigs := []*specConfig{{Name: "tasks", Path: filepath.Join(t.TempDir(), "spec.json")}}
+	writeWatchedSpec(t, specConfigs[0].Path, upstream.URL, "/task", "getTask")
+	current := newCurrentHandler(t, specConfigs)
+
+	done := make(chan string)
+	go func() {
+		done <- query(t, current, "{ getTask { name } }")
+	}()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request did not reach upstream")
+	}
+
+	writeWatchedSpec(t, specConfigs[0].Path, upstream.URL, "/other", "getOther")
+	if err := reload(current, specConfigs, loader.Options{}, types.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+
+	// in-flight request is finished by previous schema
+	if data := <-done; data != `{"data":{"getTask":{"name":"task"}}}` {
+		t.Errorf("got %s of in-flight request", data)
+	}
+	assertQuery(t, current, "{ getOther { name } }", `{"data":{"getOther":{"name":"other"}}}`)
+}
+
+func writeWatchedSpec(t *testing.T, path string, server string, specPath string, operationId string) {
+	spec := strings.NewReplacer("%SERVER%", server, "%PATH%", specPath, "%OPERATION%", operationId).Replace(watchedSpec)
+	if err := ioutil.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newCurrentHandler(t *testing.T, specConfigs []*specConfig) *currentHandler {
+	current := &currentHandler{}
+	if err := reload(current, specConfigs, loader.Options{}, types.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	return current
+}
+
+// Returns compact JSON response of query
+func query(t *testing.T, h http.Handler, query string) string {
+	body, _ := json.Marshal(map[string]string{"query": query})
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body)))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+
+	var response interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Errorf("invalid response %s: %v", recorder.Body.String(), err)
+		return ""
+	}
+	compact, _ := json.Marshal(response)
+	return string(compact)
+}
+
+func assertQuery(t *testing.T, h http.Handler, q string, want string) {
+	t.Helper()
+	if got := query(t, h, q); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}